@@ -6,12 +6,22 @@ import (
 	"github.com/Khan/genqlient/graphql"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"slices"
+	"stash-vr/internal/api/heresphere"
+	"stash-vr/internal/api/media"
+	"stash-vr/internal/audit"
 	"stash-vr/internal/build"
 	"stash-vr/internal/config"
+	"stash-vr/internal/device"
+	"stash-vr/internal/edits"
+	"stash-vr/internal/history"
 	"stash-vr/internal/library"
 	"stash-vr/internal/logger"
+	"stash-vr/internal/playback"
 	"stash-vr/internal/server"
+	"stash-vr/internal/share"
 	"stash-vr/internal/stash"
+	"stash-vr/internal/trash"
 )
 
 func Run(ctx context.Context) error {
@@ -20,13 +30,45 @@ func Run(ctx context.Context) error {
 	zerolog.DefaultContextLogger = &log.Logger
 
 	log.Info().Str("config", fmt.Sprintf("%+v", config.Application().Redacted())).Send()
+	for _, kind := range config.Application().DenyWrite {
+		if !slices.Contains(config.Writes, kind) {
+			log.Warn().Str("kind", kind).Msg("Unknown kind of change in DENY_WRITE, ignoring")
+		}
+	}
+	if denied := config.Application().DeniedWrites(); len(denied) > 0 {
+		log.Info().Strs("denied", denied).Msg("Changes to Stash are restricted")
+	}
 
-	stashClient := stash.NewClient(config.Application().StashGraphQLUrl, config.Application().StashApiKey)
+	httpClient, err := stash.NewHttpClient(config.Application().StashGraphQLUrl, stash.Credentials{
+		ApiKey:   config.Application().StashApiKey,
+		Username: config.Application().StashUsername,
+		Password: config.Application().StashPassword,
+	})
+	if err != nil {
+		return fmt.Errorf("stash http client: %w", err)
+	}
+	stashClient := stash.NewClient(config.Application().StashGraphQLUrl, httpClient)
 	logVersions(ctx, stashClient)
 
-	libraryService := library.NewService(stashClient)
+	auditLog := audit.Open(ctx)
+	trashStore := trash.Open(ctx)
+	libraryService := library.NewService(stashClient, httpClient, auditLog, trashStore)
+	if err := libraryService.DetectCapabilities(ctx); err != nil {
+		log.Warn().Err(err).Msg("Failed to detect Stash capabilities, assuming latest")
+	}
+	historyStore := history.Open(ctx)
+	playbackTracker := playback.NewTracker(ctx, libraryService, historyStore)
+	deviceStore := device.Open(ctx)
+	shareSigner := share.Open(ctx)
+	media.LoadKey(ctx)
+	heresphere.LoadTokenKey(ctx)
+	heresphereState := heresphere.NewState()
+	editQueue := edits.Open(ctx)
+	editQueue.Handle(heresphere.EditSource, heresphere.EditHandler(libraryService, heresphereState))
+	go editQueue.Run(ctx)
+	go libraryService.RunPurge(ctx)
 
-	err := server.Listen(ctx, config.Application().ListenAddress, libraryService)
+	err = server.Listen(ctx, config.Application().ListenAddress, libraryService, playbackTracker, historyStore, deviceStore, shareSigner, editQueue, heresphereState, auditLog)
 	if err != nil {
 		return fmt.Errorf("server: %w", err)
 	}
@@ -42,4 +84,12 @@ func logVersions(ctx context.Context, client graphql.Client) {
 	} else {
 		log.Info().Str("Stash version", version).Send()
 	}
+
+	if config.Application().StashApiKey == "" && config.Application().StashUsername != "" {
+		if username, err := stash.GetUsername(ctx, client); err != nil {
+			log.Warn().Err(err).Msg("Failed to verify stash login")
+		} else {
+			log.Info().Str("username", username).Msg("Logged in to Stash")
+		}
+	}
 }
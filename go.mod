@@ -6,8 +6,10 @@ require (
 	github.com/Khan/genqlient v0.8.1
 	github.com/go-chi/chi/v5 v5.2.1
 	github.com/rs/zerolog v1.34.0
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
 	github.com/spf13/pflag v1.0.6
 	github.com/spf13/viper v1.20.1
+	github.com/vektah/gqlparser/v2 v2.5.27
 	golang.org/x/image v0.28.0
 	golang.org/x/sync v0.15.0
 )
@@ -28,7 +30,6 @@ require (
 	github.com/spf13/afero v1.14.0 // indirect
 	github.com/spf13/cast v1.9.2 // indirect
 	github.com/subosito/gotenv v1.6.0 // indirect
-	github.com/vektah/gqlparser/v2 v2.5.27 // indirect
 	go.uber.org/atomic v1.11.0 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
 	golang.org/x/mod v0.25.0 // indirect
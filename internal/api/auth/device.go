@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+	"net/http"
+	"stash-vr/internal/api/internal"
+	"stash-vr/internal/device"
+)
+
+const DeviceTokenParam = "deviceToken"
+
+// DeviceMiddleware authenticates requests by the device token in the url path and keeps it in all links built
+// for the request.
+func DeviceMiddleware(deviceStore *device.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := chi.URLParam(r, DeviceTokenParam)
+			d, ok := deviceStore.Authenticate(token)
+			if !ok {
+				log.Ctx(r.Context()).Debug().Str("remoteAddr", r.RemoteAddr).Msg("Unknown or revoked device token")
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+
+			ctx := log.Ctx(r.Context()).With().Str("device", d.Name).Logger().WithContext(r.Context())
+			ctx = device.WithDevice(ctx, d)
+			ctx = internal.WithBasePath(ctx, DevicePath(token))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func DevicePath(token string) string {
+	return "/d/" + token
+}
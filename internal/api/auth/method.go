@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"stash-vr/internal/config"
+	"strings"
+)
+
+const (
+	methodBasic = "basic"
+	methodToken = "token"
+	methodIP    = "ip"
+
+	tokenQueryParam = "token"
+)
+
+// Method authenticates a request.
+type Method interface {
+	Name() string
+	// Authenticate returns the identity of the client, ok is false if the request is not authenticated.
+	Authenticate(req *http.Request) (identity string, ok bool)
+}
+
+type basicMethod struct {
+	accounts []config.Account
+}
+
+func (m basicMethod) Name() string {
+	return methodBasic
+}
+
+func (m basicMethod) Authenticate(req *http.Request) (string, bool) {
+	username, password, ok := req.BasicAuth()
+	if !ok {
+		return "", false
+	}
+	for _, a := range m.accounts {
+		if subtle.ConstantTimeCompare([]byte(a.Username), []byte(username)) == 1 &&
+			subtle.ConstantTimeCompare([]byte(a.Password), []byte(password)) == 1 {
+			return a.Username, true
+		}
+	}
+	return "", false
+}
+
+// tokenMethod accepts a static token as a bearer token or, for players that can't set headers, a query parameter.
+type tokenMethod struct {
+	tokens []string
+}
+
+func (m tokenMethod) Name() string {
+	return methodToken
+}
+
+func (m tokenMethod) Authenticate(req *http.Request) (string, bool) {
+	token := req.URL.Query().Get(tokenQueryParam)
+	if bearer, found := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer "); found {
+		token = bearer
+	}
+	if token == "" {
+		return "", false
+	}
+	for _, t := range m.tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			return "token", true
+		}
+	}
+	return "", false
+}
+
+type ipMethod struct {
+	nets []*net.IPNet
+}
+
+func newIPMethod(entries []string) (ipMethod, []string) {
+	var m ipMethod
+	var invalid []string
+	for _, e := range entries {
+		if !strings.Contains(e, "/") {
+			if ip := net.ParseIP(e); ip != nil {
+				bits := 8 * len(ip.To16())
+				if ip.To4() != nil {
+					ip, bits = ip.To4(), 32
+				}
+				m.nets = append(m.nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+				continue
+			}
+		}
+		_, n, err := net.ParseCIDR(e)
+		if err != nil {
+			invalid = append(invalid, e)
+			continue
+		}
+		m.nets = append(m.nets, n)
+	}
+	return m, invalid
+}
+
+func (m ipMethod) Name() string {
+	return methodIP
+}
+
+func (m ipMethod) Authenticate(req *http.Request) (string, bool) {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return "", false
+	}
+	for _, n := range m.nets {
+		if n.Contains(ip) {
+			return ip.String(), true
+		}
+	}
+	return "", false
+}
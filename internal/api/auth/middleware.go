@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"stash-vr/internal/config"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Middleware protects a route group with the methods configured for it. A request is let through if any of the
+// methods authenticates it. Groups without methods configured are open.
+func Middleware(ctx context.Context, group string) func(http.Handler) http.Handler {
+	methods := groupMethods(ctx, group)
+	if len(methods) == 0 {
+		return func(next http.Handler) http.Handler {
+			return next
+		}
+	}
+
+	challengeBasic := false
+	for _, m := range methods {
+		if m.Name() == methodBasic {
+			challengeBasic = true
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, m := range methods {
+				if identity, ok := m.Authenticate(r); ok {
+					ctx := log.Ctx(r.Context()).With().Str("auth", m.Name()).Str("identity", identity).Logger().WithContext(r.Context())
+					next.ServeHTTP(w, r.WithContext(ctx))
+					return
+				}
+			}
+
+			log.Ctx(r.Context()).Debug().Str("group", group).Str("remoteAddr", r.RemoteAddr).Msg("Unauthenticated request rejected")
+			if challengeBasic {
+				w.Header().Set("WWW-Authenticate", `Basic realm="Stash-VR", charset="UTF-8"`)
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		})
+	}
+}
+
+func groupMethods(ctx context.Context, group string) []Method {
+	cfg := config.Application()
+	names := cfg.AuthGroups[group]
+	methods := make([]Method, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case methodBasic:
+			if len(cfg.AuthBasic) == 0 {
+				log.Ctx(ctx).Warn().Str("group", group).Msg("Basic auth enabled but AUTH_BASIC is empty, no one will be able to log in")
+			}
+			methods = append(methods, basicMethod{accounts: cfg.AuthBasic})
+		case methodToken:
+			if len(cfg.AuthTokens) == 0 {
+				log.Ctx(ctx).Warn().Str("group", group).Msg("Token auth enabled but AUTH_TOKENS is empty")
+			}
+			methods = append(methods, tokenMethod{tokens: cfg.AuthTokens})
+		case methodIP:
+			m, invalid := newIPMethod(cfg.AuthAllowIPs)
+			if len(invalid) > 0 {
+				log.Ctx(ctx).Warn().Strs("entries", invalid).Msg("Ignoring invalid entries in AUTH_ALLOW_IPS")
+			}
+			if len(m.nets) == 0 {
+				log.Ctx(ctx).Warn().Str("group", group).Msg("IP auth enabled but AUTH_ALLOW_IPS is empty")
+			}
+			methods = append(methods, m)
+		default:
+			log.Ctx(ctx).Warn().Str("group", group).Str("method", name).Msg("Unknown auth method, ignoring")
+		}
+	}
+	if len(names) > 0 && len(methods) == 0 {
+		log.Ctx(ctx).Warn().Str("group", group).Msg("No valid auth methods, denying all requests")
+		methods = append(methods, denyMethod{})
+	}
+	if len(methods) > 0 {
+		log.Ctx(ctx).Info().Str("group", group).Strs("methods", names).Msg("Authentication enabled")
+	}
+	return methods
+}
+
+// denyMethod authenticates nothing, used to fail closed on misconfiguration.
+type denyMethod struct{}
+
+func (denyMethod) Name() string {
+	return "deny"
+}
+
+func (denyMethod) Authenticate(*http.Request) (string, bool) {
+	return "", false
+}
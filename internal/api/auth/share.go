@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+	"net/http"
+	"stash-vr/internal/api/internal"
+	"stash-vr/internal/share"
+)
+
+const ShareTokenParam = "shareToken"
+
+// ShareMiddleware authenticates requests by the share token in the url path and keeps it in all links built for the
+// request. Handlers restrict what they serve by the grant put in the request context.
+func ShareMiddleware(signer *share.Signer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := chi.URLParam(r, ShareTokenParam)
+			g, err := signer.Verify(token)
+			if err != nil {
+				log.Ctx(r.Context()).Debug().Err(err).Str("remoteAddr", r.RemoteAddr).Msg("Rejected share link")
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+
+			ctx := log.Ctx(r.Context()).With().Str("share", g.Name).Logger().WithContext(r.Context())
+			ctx = share.WithGrant(ctx, g)
+			ctx = internal.WithBasePath(ctx, SharePath(token))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func SharePath(token string) string {
+	return "/s/" + token
+}
@@ -5,11 +5,14 @@ import (
 	"github.com/rs/zerolog/log"
 	"net/http"
 	"stash-vr/internal/api/internal"
+	"stash-vr/internal/history"
 	"stash-vr/internal/library"
+	"stash-vr/internal/share"
 )
 
 type httpHandler struct {
 	LibraryService *library.Service
+	HistoryStore   *history.Store
 }
 
 func (h httpHandler) indexHandler(w http.ResponseWriter, req *http.Request) {
@@ -22,6 +25,7 @@ func (h httpHandler) indexHandler(w http.ResponseWriter, req *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	sections = share.Restrict(ctx, sections)
 
 	vds, err := h.LibraryService.GetScenes(ctx)
 	if err != nil {
@@ -30,7 +34,7 @@ func (h httpHandler) indexHandler(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	dto, err := buildIndex(sections, vds, baseUrl)
+	dto, err := buildIndex(ctx, sections, vds, baseUrl)
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("failed to build index")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -49,13 +53,22 @@ func (h httpHandler) videoDataHandler(w http.ResponseWriter, req *http.Request)
 	sceneId := chi.URLParam(req, "videoId")
 	baseUrl := internal.GetBaseUrl(req)
 
+	if share.IsReadOnly(ctx) {
+		sections, err := h.LibraryService.GetCachedSections(ctx)
+		if err != nil || !share.Allows(ctx, sections, sceneId) {
+			log.Ctx(ctx).Debug().Err(err).Msg("Scene not shared")
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+	}
+
 	vd, err := h.LibraryService.GetScene(ctx, sceneId, false)
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("failed to get scene data")
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
-	dto, err := buildVideoData(vd, baseUrl)
+	dto, err := buildVideoData(ctx, vd, baseUrl, h.HistoryStore)
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("failed to build video data")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -1,8 +1,9 @@
 package deovr
 
 import (
+	"context"
+	"stash-vr/internal/api/media"
 	"stash-vr/internal/library"
-	"stash-vr/internal/stash"
 	"stash-vr/internal/util"
 )
 
@@ -24,28 +25,32 @@ type previewDataDto struct {
 	VideoUrl     string  `json:"video_url"`
 }
 
-func buildIndex(sections []library.Section, vds map[string]*library.VideoData, baseUrl string) (indexDto, error) {
+func buildIndex(ctx context.Context, sections []library.Section, vds map[string]*library.VideoData, baseUrl string) (indexDto, error) {
 	index := indexDto{Authorized: "1", Scenes: make([]sceneDto, len(sections))}
 
 	for i, section := range sections {
 		s := sceneDto{
 			Name: section.Name,
-			List: make([]previewDataDto, len(section.Ids)),
+			List: make([]previewDataDto, 0, len(section.Ids)),
 		}
-		index.Scenes[i] = s
 
-		for j, sectionSceneId := range section.Ids {
-			vd := vds[sectionSceneId]
-			s.List[j] = previewDataDto{
+		for _, sectionSceneId := range section.Ids {
+			vd, ok := vds[sectionSceneId]
+			if !ok || vd == nil {
+				continue
+			}
+			p := previewDataDto{
 				Id:          vd.SceneParts.Id,
 				Title:       vd.Title(),
 				VideoLength: int(vd.SceneParts.Files[0].Duration),
 				VideoUrl:    getVideoDataUrl(baseUrl, vd.Id()),
 			}
 			if vd.SceneParts.Paths.Screenshot != nil {
-				s.List[j].ThumbnailUrl = util.Ptr(stash.ApiKeyed(*vd.SceneParts.Paths.Screenshot))
+				p.ThumbnailUrl = util.Ptr(media.Url(ctx, baseUrl, vd.Id(), media.KindScreenshot, *vd.SceneParts.Paths.Screenshot))
 			}
+			s.List = append(s.List, p)
 		}
+		index.Scenes[i] = s
 	}
 
 	return index, nil
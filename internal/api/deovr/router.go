@@ -4,11 +4,12 @@ import (
 	"github.com/go-chi/chi/v5"
 	"net/http"
 	"stash-vr/internal/api/internal"
+	"stash-vr/internal/history"
 	"stash-vr/internal/library"
 )
 
-func Router(libraryService *library.Service) http.Handler {
-	httpHandler := httpHandler{libraryService}
+func Router(libraryService *library.Service, historyStore *history.Store) http.Handler {
+	httpHandler := httpHandler{libraryService, historyStore}
 	r := chi.NewRouter()
 
 	r.Get("/", internal.LogRoute("index", httpHandler.indexHandler))
@@ -1,9 +1,12 @@
 package deovr
 
 import (
+	"context"
 	"fmt"
 	"stash-vr/internal/api/heatmap"
 	"stash-vr/internal/api/internal"
+	"stash-vr/internal/api/media"
+	"stash-vr/internal/history"
 	"stash-vr/internal/library"
 	"stash-vr/internal/stash"
 	"stash-vr/internal/util"
@@ -44,7 +47,7 @@ type videoSourceDto struct {
 	Url        string `json:"url"`
 }
 
-func buildVideoData(vd *library.VideoData, baseUrl string) (*videoDataDto, error) {
+func buildVideoData(ctx context.Context, vd *library.VideoData, baseUrl string, historyStore *history.Store) (*videoDataDto, error) {
 	videoId := vd.Id()
 	if len(vd.SceneParts.Files) == 0 {
 		return nil, fmt.Errorf("scene %s has no files", videoId)
@@ -59,27 +62,26 @@ func buildVideoData(vd *library.VideoData, baseUrl string) (*videoDataDto, error
 		SkipIntro:   0,
 	}
 
-	if vd.SceneParts.Paths.Screenshot != nil {
-		if vd.SceneParts.Interactive && vd.SceneParts.Paths.Interactive_heatmap != nil {
-			dto.ThumbnailUrl = util.Ptr(heatmap.GetCoverUrl(baseUrl, videoId))
-		} else {
-			dto.ThumbnailUrl = util.Ptr(stash.ApiKeyed(*vd.SceneParts.Paths.Screenshot))
-		}
+	dto.ThumbnailUrl = heatmap.GetThumbnailUrl(ctx, baseUrl, vd, historyStore)
+
+	if vd.SceneParts.Resume_time != nil {
+		dto.SkipIntro = int(*vd.SceneParts.Resume_time)
 	}
 
 	if vd.SceneParts.Paths.Preview != nil {
-		dto.VideoPreview = util.Ptr(stash.ApiKeyed(*vd.SceneParts.Paths.Preview))
+		dto.VideoPreview = util.Ptr(media.Url(ctx, baseUrl, videoId, media.KindPreview, *vd.SceneParts.Paths.Preview))
 	}
 
-	setStreamSources(vd, &dto)
+	setStreamSources(ctx, vd, &dto, baseUrl)
 	setMarkers(vd, &dto)
 	set3DFormat(vd, &dto)
 
 	return &dto, nil
 }
 
-func setStreamSources(vd *library.VideoData, dto *videoDataDto) {
+func setStreamSources(ctx context.Context, vd *library.VideoData, dto *videoDataDto, baseUrl string) {
 	streams := []stash.Stream{stash.GetTranscodingStream(vd.SceneParts), stash.GetDirectStream(vd.SceneParts)}
+	kinds := []media.Kind{media.KindTranscode, media.KindStream}
 	dto.Encodings = make([]encodingDto, len(streams))
 	for i, stream := range streams {
 		dto.Encodings[i] = encodingDto{
@@ -89,7 +91,7 @@ func setStreamSources(vd *library.VideoData, dto *videoDataDto) {
 		for j, source := range stream.Sources {
 			dto.Encodings[i].VideoSources[j] = videoSourceDto{
 				Resolution: source.Resolution,
-				Url:        source.Url,
+				Url:        media.Url(ctx, baseUrl, vd.Id(), kinds[i], source.Url),
 			}
 		}
 	}
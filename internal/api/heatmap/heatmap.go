@@ -18,14 +18,14 @@ var errImageNotFound = errors.New("image not found")
 var errScreenshotImageNotFound = errors.New("screenshot image not found")
 var errHeatmapImageNotFound = errors.New("heatmap image not found")
 
-func fetchImage(ctx context.Context, fileUrl string) (image.Image, error) {
+func fetchImage(ctx context.Context, httpClient *http.Client, fileUrl string) (image.Image, error) {
 	log.Ctx(ctx).Trace().Str("url", fileUrl).Msg("Fetching image")
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileUrl, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -44,7 +44,7 @@ func fetchImage(ctx context.Context, fileUrl string) (image.Image, error) {
 	return img, nil
 }
 
-func buildHeatmapCover(ctx context.Context, coverUrl string, heatmapUrl string) (image.Image, error) {
+func buildHeatmapCover(ctx context.Context, httpClient *http.Client, coverUrl string, heatmapUrl string) (image.Image, error) {
 	chCover := make(chan draw.Image, 1)
 	chHeatmap := make(chan image.Image, 1)
 
@@ -52,7 +52,7 @@ func buildHeatmapCover(ctx context.Context, coverUrl string, heatmapUrl string)
 
 	g.Go(func() error {
 		defer close(chCover)
-		cover, err := fetchImage(log.Ctx(ctx).With().Str("image", "cover").Logger().WithContext(ctx), coverUrl)
+		cover, err := fetchImage(log.Ctx(ctx).With().Str("image", "cover").Logger().WithContext(ctx), httpClient, coverUrl)
 		if err != nil {
 			return errors.Join(errScreenshotImageNotFound, err)
 		}
@@ -67,7 +67,7 @@ func buildHeatmapCover(ctx context.Context, coverUrl string, heatmapUrl string)
 
 	g.Go(func() error {
 		defer close(chHeatmap)
-		heatmap, err := fetchImage(log.Ctx(ctx).With().Str("image", "heatmap").Logger().WithContext(ctx), heatmapUrl)
+		heatmap, err := fetchImage(log.Ctx(ctx).With().Str("image", "heatmap").Logger().WithContext(ctx), httpClient, heatmapUrl)
 		if err != nil {
 			return errors.Join(errHeatmapImageNotFound, err)
 		}
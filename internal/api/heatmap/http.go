@@ -1,21 +1,37 @@
 package heatmap
 
 import (
+	"context"
 	"errors"
 	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog/log"
+	"image"
 	"image/jpeg"
 	"net/http"
 	"stash-vr/internal/api/internal"
+	"stash-vr/internal/api/media"
+	"stash-vr/internal/config"
+	"stash-vr/internal/history"
 	"stash-vr/internal/library"
+	"stash-vr/internal/share"
 	"stash-vr/internal/stash"
+	"stash-vr/internal/util"
 )
 
-func CoverHandler(libraryService *library.Service) http.HandlerFunc {
+func CoverHandler(libraryService *library.Service, historyStore *history.Store) http.HandlerFunc {
 	f := func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		sceneId := chi.URLParam(r, "videoId")
 
+		if share.IsReadOnly(ctx) {
+			sections, err := libraryService.GetCachedSections(ctx)
+			if err != nil || !share.Allows(ctx, sections, sceneId) {
+				log.Ctx(ctx).Debug().Err(err).Msg("Scene not shared")
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+		}
+
 		vd, err := libraryService.GetScene(ctx, sceneId, false)
 		if err != nil {
 			log.Ctx(ctx).Debug().Msg("Scene not found")
@@ -24,7 +40,22 @@ func CoverHandler(libraryService *library.Service) http.HandlerFunc {
 		}
 
 		p := vd.SceneParts.Paths
-		cover, err := buildHeatmapCover(ctx, stash.ApiKeyed(*p.Screenshot), stash.ApiKeyed(*p.Interactive_heatmap))
+		if p.Screenshot == nil {
+			log.Ctx(ctx).Debug().Msg("Scene has no cover")
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var cover image.Image
+		if hasInteractiveHeatmap(vd) {
+			cover, err = buildHeatmapCover(ctx, libraryService.HttpClient, stash.ApiKeyed(*p.Screenshot), stash.ApiKeyed(*p.Interactive_heatmap))
+		} else if h, ok := historyStore.Histogram(sceneId, history.ReplayBuckets); ok && config.Application().ReplayHeatmap {
+			cover, err = buildReplayCover(ctx, libraryService.HttpClient, stash.ApiKeyed(*p.Screenshot), h)
+		} else {
+			log.Ctx(ctx).Debug().Msg("Scene has no heatmap")
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
 		if err != nil {
 			log.Ctx(ctx).Err(err).Msg("buildHeatmapCover")
 			if errors.Is(err, errImageNotFound) {
@@ -46,3 +77,18 @@ func CoverHandler(libraryService *library.Service) http.HandlerFunc {
 func GetCoverUrl(baseUrl string, sceneId string) string {
 	return baseUrl + "/cover/" + sceneId
 }
+
+// GetThumbnailUrl returns the url to the cover of a scene, overlaid with a heatmap if one is available.
+func GetThumbnailUrl(ctx context.Context, baseUrl string, vd *library.VideoData, historyStore *history.Store) *string {
+	if vd.SceneParts.Paths.Screenshot == nil {
+		return nil
+	}
+	if hasInteractiveHeatmap(vd) || (config.Application().ReplayHeatmap && historyStore.HasHistory(vd.Id())) {
+		return util.Ptr(GetCoverUrl(baseUrl, vd.Id()))
+	}
+	return util.Ptr(media.Url(ctx, baseUrl, vd.Id(), media.KindScreenshot, *vd.SceneParts.Paths.Screenshot))
+}
+
+func hasInteractiveHeatmap(vd *library.VideoData) bool {
+	return vd.SceneParts.Interactive && vd.SceneParts.Paths.Interactive_heatmap != nil
+}
@@ -0,0 +1,66 @@
+package heatmap
+
+import (
+	"context"
+	"errors"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/image/draw"
+	"image"
+	"image/color"
+	"net/http"
+	"stash-vr/internal/history"
+)
+
+const (
+	replayHeightPx = 15
+)
+
+// replayGradient runs from rarely to most watched.
+var replayGradient = []color.RGBA{
+	{R: 0x1e, G: 0x40, B: 0xff, A: 0xff},
+	{R: 0x00, G: 0xc8, B: 0xc8, A: 0xff},
+	{R: 0x3c, G: 0xd2, B: 0x3c, A: 0xff},
+	{R: 0xff, G: 0xd7, B: 0x00, A: 0xff},
+	{R: 0xff, G: 0x28, B: 0x28, A: 0xff},
+}
+
+var unwatchedColor = color.RGBA{A: 0xff}
+
+func renderReplayHeatmap(h history.Histogram) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, len(h.Counts), replayHeightPx))
+	maxCount := h.Max()
+	for x, c := range h.Counts {
+		col := unwatchedColor
+		if c > 0 && maxCount > 0 {
+			col = gradientAt(float64(c) / float64(maxCount))
+		}
+		for y := 0; y < replayHeightPx; y++ {
+			img.SetRGBA(x, y, col)
+		}
+	}
+	return img
+}
+
+func gradientAt(v float64) color.RGBA {
+	pos := v * float64(len(replayGradient)-1)
+	i := min(int(pos), len(replayGradient)-2)
+	f := pos - float64(i)
+	a, b := replayGradient[i], replayGradient[i+1]
+	lerp := func(x, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*f)
+	}
+	return color.RGBA{R: lerp(a.R, b.R), G: lerp(a.G, b.G), B: lerp(a.B, b.B), A: 0xff}
+}
+
+func buildReplayCover(ctx context.Context, httpClient *http.Client, coverUrl string, h history.Histogram) (image.Image, error) {
+	cover, err := fetchImage(log.Ctx(ctx).With().Str("image", "cover").Logger().WithContext(ctx), httpClient, coverUrl)
+	if err != nil {
+		return nil, errors.Join(errScreenshotImageNotFound, err)
+	}
+	dest, ok := cover.(draw.Image)
+	if !ok {
+		dest = image.NewRGBA(cover.Bounds())
+		draw.Copy(dest, image.Pt(0, 0), cover, cover.Bounds(), draw.Src, nil)
+	}
+	return overlay(dest, renderReplayHeatmap(h)), nil
+}
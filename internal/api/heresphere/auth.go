@@ -0,0 +1,189 @@
+package heresphere
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"stash-vr/internal/api/internal"
+	"stash-vr/internal/config"
+	"stash-vr/internal/device"
+	"stash-vr/internal/share"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	accessDenied   = -1
+	accessLogin    = 0
+	accessGranted  = 1
+	authHeader     = "auth-token"
+	tokenSeparator = "."
+	keyFile        = "heresphere.key"
+	keySize        = 32
+)
+
+// tokenKey signs login tokens. LoadTokenKey replaces it with the key saved to CONFIG_PATH so that logins survive a
+// restart, without CONFIG_PATH it's created on start and HereSphere asks to log in again after a restart.
+var tokenKey = func() []byte {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+	return key
+}()
+
+// LoadTokenKey reads the key signing login tokens from CONFIG_PATH, saving the current one if there is none. It must
+// be called before serving requests.
+func LoadTokenKey(ctx context.Context) {
+	if config.Application().ConfigPath == "" {
+		return
+	}
+	path := filepath.Join(config.Application().ConfigPath, keyFile)
+	key, err := os.ReadFile(path)
+	if err == nil && len(key) == keySize {
+		tokenKey = key
+		return
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to read heresphere key")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("error creating config directory")
+		return
+	}
+	if err := os.WriteFile(path, tokenKey, 0o600); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to save heresphere key")
+	}
+}
+
+type authRequestDto struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type authResponseDto struct {
+	AuthToken string `json:"auth-token,omitempty"`
+	Access    int    `json:"access"`
+}
+
+type accessDto struct {
+	Access int `json:"access"`
+}
+
+func isAuthEnabled() bool {
+	return len(config.Application().HeresphereAccounts) > 0
+}
+
+func (h *httpHandler) authHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+
+	if !isAuthEnabled() {
+		http.NotFound(w, req)
+		return
+	}
+
+	authReq, err := internal.UnmarshalBody[authRequestDto](req)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("Failed to parse auth body")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	resp := authResponseDto{Access: accessDenied}
+	if account, ok := findAccount(authReq.Username); ok && subtle.ConstantTimeCompare([]byte(account.Password), []byte(authReq.Password)) == 1 {
+		resp = authResponseDto{AuthToken: issueToken(account), Access: accessGranted}
+		log.Ctx(ctx).Info().Str("username", account.Username).Msg("HereSphere login")
+	} else {
+		log.Ctx(ctx).Warn().Str("username", authReq.Username).Msg("HereSphere login failed")
+	}
+
+	if err := internal.WriteJson(ctx, w, resp); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("write")
+	}
+}
+
+// requireAuth rejects requests without a valid auth token if HERESPHERE_ACCOUNTS is set, unless made by a paired
+// device or with a share link. HereSphere prompts for login when it receives access 0.
+func requireAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if !isAuthEnabled() {
+			next.ServeHTTP(w, req)
+			return
+		}
+		if _, paired := device.FromContext(req.Context()); paired || share.IsReadOnly(req.Context()) {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		ctx := req.Context()
+		username, ok := validateToken(req.Header.Get(authHeader))
+		if !ok {
+			log.Ctx(ctx).Debug().Msg("Missing or invalid auth token")
+			if err := internal.WriteJson(ctx, w, accessDto{Access: accessLogin}); err != nil {
+				log.Ctx(ctx).Error().Err(err).Msg("write")
+			}
+			return
+		}
+
+		ctx = log.Ctx(ctx).With().Str("username", username).Logger().WithContext(ctx)
+		next.ServeHTTP(w, req.WithContext(ctx))
+	}
+}
+
+func findAccount(username string) (config.Account, bool) {
+	for _, a := range config.Application().HeresphereAccounts {
+		if a.Username == username {
+			return a, true
+		}
+	}
+	return config.Account{}, false
+}
+
+// issueToken returns a token for the account that expires after HERESPHERE_TOKEN_TTL. It's signed with tokenKey and
+// covers a hash of the password, so that it is revoked early by changing the password.
+func issueToken(account config.Account) string {
+	expires := strconv.FormatInt(time.Now().Add(config.Application().HeresphereTokenTTL).Unix(), 10)
+	return strings.Join([]string{base64.RawURLEncoding.EncodeToString([]byte(account.Username)), expires, sign(account, expires)}, tokenSeparator)
+}
+
+func validateToken(token string) (string, bool) {
+	parts := strings.Split(token, tokenSeparator)
+	if len(parts) != 3 {
+		return "", false
+	}
+	encodedUsername, expires, signature := parts[0], parts[1], parts[2]
+	username, err := base64.RawURLEncoding.DecodeString(encodedUsername)
+	if err != nil {
+		return "", false
+	}
+	account, ok := findAccount(string(username))
+	if !ok {
+		return "", false
+	}
+	if !hmac.Equal([]byte(signature), []byte(sign(account, expires))) {
+		return "", false
+	}
+	if unix, err := strconv.ParseInt(expires, 10, 64); err != nil || time.Now().After(time.Unix(unix, 0)) {
+		return "", false
+	}
+	return account.Username, true
+}
+
+func sign(account config.Account, expires string) string {
+	password := sha256.Sum256([]byte(account.Password))
+	mac := hmac.New(sha256.New, tokenKey)
+	mac.Write([]byte("heresphere:" + account.Username + tokenSeparator + expires + tokenSeparator))
+	mac.Write(password[:])
+	return hex.EncodeToString(mac.Sum(nil))
+}
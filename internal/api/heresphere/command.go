@@ -0,0 +1,147 @@
+package heresphere
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"stash-vr/internal/api/internal"
+	"stash-vr/internal/config"
+	"stash-vr/internal/library"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const commandPrefix = "/"
+
+// command is run when a tag starting with "/" is added in HereSphere, e.g. "/rate 4.5" or "/rate:4.5". The tag itself
+// isn't saved.
+type command struct {
+	name  string
+	usage string
+	help  string
+	run   func(ctx context.Context, cs *library.ChangeSet, arg string) ([]tagDto, error)
+}
+
+// commands is filled in init as /help refers to it.
+var commands []command
+
+func init() {
+	commands = []command{
+		{name: "/o", help: "Increment o-count", run: noArg(func(cs *library.ChangeSet) { cs.IncrementO() })},
+		{name: "/o-", help: "Decrement o-count", run: noArg(func(cs *library.ChangeSet) { cs.DecrementO() })},
+		{name: "/play+", help: "Increment play count", run: noArg(func(cs *library.ChangeSet) { cs.IncrementPlayCount() })},
+		{name: "/play-", help: "Decrement play count", run: noArg(func(cs *library.ChangeSet) { cs.DecrementPlayCount() })},
+		{name: "/org", help: "Set organized", run: noArg(func(cs *library.ChangeSet) { cs.SetOrganized(true) })},
+		{name: "/rate", usage: "/rate <0-5>", help: "Set rating, 0 removes it", run: rateCommand},
+		{name: "/later", help: "Tag to watch later", run: laterCommand},
+		{name: "/help", help: "List commands", run: helpCommand},
+	}
+}
+
+func noArg(f func(cs *library.ChangeSet)) func(ctx context.Context, cs *library.ChangeSet, arg string) ([]tagDto, error) {
+	return func(ctx context.Context, cs *library.ChangeSet, arg string) ([]tagDto, error) {
+		if arg != "" {
+			return nil, errors.New("takes no argument")
+		}
+		f(cs)
+		return nil, nil
+	}
+}
+
+func rateCommand(ctx context.Context, cs *library.ChangeSet, arg string) ([]tagDto, error) {
+	rating, err := strconv.ParseFloat(arg, 32)
+	if err != nil || rating < 0 || rating > 5 {
+		return nil, fmt.Errorf("rating must be a number from 0 to 5, got '%s'", arg)
+	}
+	if rating == 0 {
+		cs.SetRating(nil)
+	} else {
+		r := float32(rating)
+		cs.SetRating(&r)
+	}
+	return nil, nil
+}
+
+func laterCommand(ctx context.Context, cs *library.ChangeSet, arg string) ([]tagDto, error) {
+	if arg != "" {
+		return nil, errors.New("takes no argument")
+	}
+	tag := config.Application().WatchLaterTag
+	if tag == "" {
+		return nil, errors.New("WATCH_LATER_TAG is not set")
+	}
+	cs.SetTags(append(cs.Tags(), tag))
+	return nil, nil
+}
+
+func helpCommand(ctx context.Context, cs *library.ChangeSet, arg string) ([]tagDto, error) {
+	tags := make([]tagDto, len(commands))
+	for i, c := range commands {
+		tags[i] = tagDto{Name: internal.LegendHelp + seperator + c.String()}
+	}
+	return tags, nil
+}
+
+func (c command) String() string {
+	return fmt.Sprintf("%s - %s", cmp.Or(c.usage, c.name), c.help)
+}
+
+func isCommand(tagName string) bool {
+	return strings.HasPrefix(tagName, commandPrefix)
+}
+
+// runCommand runs the command of a tag and returns tags to show in HereSphere, an Error tag if it failed.
+func runCommand(ctx context.Context, cs *library.ChangeSet, tagName string) []tagDto {
+	name, arg, _ := strings.Cut(strings.TrimSpace(tagName), " ")
+	if before, after, found := strings.Cut(name, seperator); found {
+		name, arg = before, after
+	}
+	arg = strings.TrimSpace(arg)
+
+	i := slices.IndexFunc(commands, func(c command) bool {
+		return strings.EqualFold(c.name, name)
+	})
+	if i < 0 {
+		return []tagDto{errorTag(fmt.Errorf("unknown command %s, add /help for a list", name))}
+	}
+	tags, err := commands[i].run(ctx, cs, arg)
+	if err != nil {
+		return []tagDto{errorTag(fmt.Errorf("%s: %w", commands[i].name, err))}
+	}
+	return tags
+}
+
+func errorTag(err error) tagDto {
+	return tagDto{Name: internal.LegendError + seperator + err.Error()}
+}
+
+// feedbacks holds tags for HereSphere about commands, shown the next time the scene is fetched.
+type feedbacks struct {
+	mu        sync.Mutex
+	bySceneId map[string][]tagDto
+}
+
+func newFeedbacks() *feedbacks {
+	return &feedbacks{bySceneId: make(map[string][]tagDto)}
+}
+
+func (f *feedbacks) add(sceneId string, tags ...tagDto) {
+	if len(tags) == 0 {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.bySceneId[sceneId] = append(f.bySceneId[sceneId], tags...)
+}
+
+// take returns the tags for a scene and forgets them, so that they are shown once.
+func (f *feedbacks) take(sceneId string) []tagDto {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tags := f.bySceneId[sceneId]
+	delete(f.bySceneId, sceneId)
+	return tags
+}
@@ -0,0 +1,129 @@
+package heresphere
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"stash-vr/internal/audit"
+	"stash-vr/internal/edits"
+	"stash-vr/internal/library"
+	"stash-vr/internal/stash"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+const EditSource = "heresphere"
+
+// State is what was served to HereSphere and the feedback to show it next, shared by all mounts of the router and the
+// edit handler applying edits outside of requests.
+type State struct {
+	served   *servedStates
+	feedback *feedbacks
+}
+
+func NewState() *State {
+	return &State{served: newServedStates(), feedback: newFeedbacks()}
+}
+
+// edit is what HereSphere sent together with the state the client that sent it was served, if known, queued to be
+// merged into the scene in Stash.
+type edit struct {
+	Request videoDataRequestDto `json:"request"`
+	Client  string              `json:"client,omitempty"`
+	Served  *servedState        `json:"served,omitempty"`
+}
+
+func (h *httpHandler) enqueueEdit(ctx context.Context, client string, videoId string, vdReq videoDataRequestDto) {
+	if vdReq.Rating == nil && vdReq.IsFavorite == nil && vdReq.Tags == nil {
+		return
+	}
+	e := edit{Request: vdReq, Client: client}
+	if state, ok := h.state.served.get(client, videoId); ok {
+		e.Served = &state
+	}
+	if err := h.editQueue.Enqueue(ctx, videoId, EditSource, summarize(vdReq), e); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("Failed to queue edit")
+	}
+}
+
+func summarize(vdReq videoDataRequestDto) string {
+	var parts []string
+	if vdReq.Rating != nil {
+		parts = append(parts, fmt.Sprintf("rating %.1f", *vdReq.Rating))
+	}
+	if vdReq.IsFavorite != nil {
+		parts = append(parts, fmt.Sprintf("favorite %t", *vdReq.IsFavorite))
+	}
+	if vdReq.Tags != nil {
+		parts = append(parts, fmt.Sprintf("%d tags", len(*vdReq.Tags)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// EditHandler merges edits sent by HereSphere into the current state of the scene in Stash. Edits to fields that were
+// changed in Stash since the state HereSphere was served are rejected.
+func EditHandler(libraryService *library.Service, state *State) edits.HandlerFunc {
+	return func(ctx context.Context, queued edits.Edit) error {
+		ctx = audit.WithSource(ctx, auditSource(queued.Device))
+		var e edit
+		if err := json.Unmarshal(queued.Payload, &e); err != nil {
+			return fmt.Errorf("malformed edit: %w", err)
+		}
+		vdReq := e.Request
+
+		current, err := libraryService.GetScene(ctx, queued.SceneId, true)
+		if err != nil {
+			return err
+		}
+		var base servedState
+		if e.Served != nil {
+			base = *e.Served
+		}
+		m := newMergeBase(base, e.Served != nil, current)
+		if e.Served == nil {
+			log.Ctx(ctx).Debug().Msg("Scene not served since startup, merging against current state")
+		} else if m.editedInStash {
+			log.Ctx(ctx).Info().Msg("Scene was edited in Stash since it was served, merging")
+		}
+
+		cs, err := libraryService.NewChangeSet(ctx, queued.SceneId)
+		if err != nil {
+			return err
+		}
+		if vdReq.Rating != nil && !m.conflicts(ctx, "rating", m.ratingChangedInStash()) {
+			cs.SetRating(vdReq.Rating)
+		}
+		if vdReq.IsFavorite != nil {
+			cs.SetFavorite(ctx, *vdReq.IsFavorite)
+		}
+		if vdReq.Tags != nil {
+			state.feedback.add(queued.SceneId, processIncomingTags(ctx, cs, vdReq, m)...)
+		}
+		if err := cs.Apply(ctx); err != nil {
+			if !stash.IsRetryable(err) {
+				state.feedback.add(queued.SceneId, errorTag(fmt.Errorf("edit failed: %w", err)))
+			}
+			return err
+		}
+		for _, err := range cs.Warnings() {
+			state.feedback.add(queued.SceneId, errorTag(err))
+		}
+
+		vd, err := libraryService.GetScene(ctx, queued.SceneId, true)
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("Failed to refetch scene")
+			return nil
+		}
+		state.served.set(e.Client, vd)
+		return nil
+	}
+}
+
+// auditSource returns who changes made by HereSphere are attributed to in the audit log.
+func auditSource(deviceName string) string {
+	if deviceName == "" {
+		return "HereSphere"
+	}
+	return fmt.Sprintf("HereSphere (%s)", deviceName)
+}
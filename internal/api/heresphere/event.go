@@ -1,5 +1,14 @@
 package heresphere
 
+import (
+	"context"
+	"net"
+	"net/http"
+	"stash-vr/internal/device"
+	"stash-vr/internal/playback"
+	"time"
+)
+
 type event int
 
 const (
@@ -33,3 +42,62 @@ type playbackEvent struct {
 	Utc           float64 `json:"utc,omitempty"`
 	ConnectionKey string  `json:"connectionKey,omitempty"`
 }
+
+// position returns the player position in seconds.
+func (ev playbackEvent) position() float64 {
+	return float64(ev.Time) / 1000
+}
+
+func (ev playbackEvent) speed() float64 {
+	if ev.Speed <= 0 {
+		return 1
+	}
+	return float64(ev.Speed)
+}
+
+func (ev playbackEvent) toPlayback(req *http.Request) playback.Event {
+	return playback.Event{Position: ev.position(), At: ev.at(), Speed: ev.speed(), Device: ev.device(req.Context()), Client: ev.client(req)}
+}
+
+// client identifies the player by the paired device, the HereSphere username or, failing those, its address.
+func (ev playbackEvent) client(req *http.Request) string {
+	if _, paired := device.FromContext(req.Context()); !paired && ev.Username != "" {
+		return "user:" + ev.Username
+	}
+	return clientOf(req)
+}
+
+// clientOf identifies the player making req by the paired device, the user it's logged in as or, failing those, its
+// address.
+func clientOf(req *http.Request) string {
+	if d, ok := device.FromContext(req.Context()); ok {
+		return "device:" + d.Id
+	}
+	if username, ok := validateToken(req.Header.Get(authHeader)); ok {
+		return "user:" + username
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	return "addr:" + host
+}
+
+// device returns the name of the paired device or the HereSphere username, if logged in, to tell headsets apart.
+func (ev playbackEvent) device(ctx context.Context) string {
+	if d, ok := device.FromContext(ctx); ok {
+		return d.Name
+	}
+	if ev.Username != "" {
+		return ev.Username
+	}
+	return "HereSphere"
+}
+
+// at returns when the event occurred according to the player, falling back to local time if not provided.
+func (ev playbackEvent) at() time.Time {
+	if ev.Utc <= 0 {
+		return time.Now()
+	}
+	return time.UnixMilli(int64(ev.Utc))
+}
@@ -2,31 +2,40 @@ package heresphere
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog/log"
+	"maps"
 	"net/http"
 	"net/url"
 	"stash-vr/internal/api/internal"
+	"stash-vr/internal/audit"
+	"stash-vr/internal/device"
+	"stash-vr/internal/edits"
+	"stash-vr/internal/history"
 	"stash-vr/internal/library"
+	"stash-vr/internal/playback"
+	"stash-vr/internal/share"
 	"stash-vr/internal/stash"
 	"stash-vr/internal/util"
 	"strings"
 )
 
 type httpHandler struct {
-	libraryService *library.Service
-	ps             *playbackState
+	libraryService  *library.Service
+	playbackTracker *playback.Tracker
+	historyStore    *history.Store
+	editQueue       *edits.Queue
+	state           *State
 }
 
-var minPlayFraction *float64
-
 func (h *httpHandler) indexHandler(w http.ResponseWriter, req *http.Request) {
 	ctx := req.Context()
 	baseUrl := internal.GetBaseUrl(req)
 
 	mpf := stash.GetMinPlayPercent(ctx, h.libraryService.StashClient) / 100
-	minPlayFraction = &mpf
+	h.playbackTracker.SetMinPlayFraction(mpf)
 
 	sections, err := h.libraryService.GetSections(ctx)
 	if err != nil {
@@ -34,6 +43,7 @@ func (h *httpHandler) indexHandler(w http.ResponseWriter, req *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	sections = share.Restrict(ctx, sections)
 
 	go func() {
 		ctx := context.Background()
@@ -66,6 +76,18 @@ func (h *httpHandler) scanHandler(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if share.IsReadOnly(ctx) {
+		sections, err := h.libraryService.GetCachedSections(ctx)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to get sections")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		maps.DeleteFunc(vds, func(id string, _ *library.VideoData) bool {
+			return !share.Allows(ctx, sections, id)
+		})
+	}
+
 	dto, err := buildScan(ctx, vds, baseUrl)
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("failed to build scan")
@@ -90,18 +112,30 @@ func (h *httpHandler) videoDataHandler(w http.ResponseWriter, req *http.Request)
 		return
 	}
 
-	if vdReq, err := internal.UnmarshalBody[videoDataRequestDto](req); err != nil {
+	if !h.isSceneAllowed(ctx, videoId) {
+		log.Ctx(ctx).Debug().Msg("Scene not shared")
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if share.IsReadOnly(ctx) {
+		log.Ctx(ctx).Trace().Msg("Read only, ignoring updates")
+	} else if vdReq, err := internal.UnmarshalBody[videoDataRequestDto](req); err != nil {
 		log.Ctx(ctx).Warn().Err(err).Msg("Failed to parse request body")
 	} else {
 		if vdReq.DeleteFile != nil && *vdReq.DeleteFile {
-			if err = h.libraryService.Delete(ctx, videoId); err != nil {
+			d, _ := device.FromContext(ctx)
+			if err = h.libraryService.Delete(audit.WithSource(ctx, auditSource(d.Name)), videoId); errors.Is(err, library.ErrWriteDenied) {
+				log.Ctx(ctx).Info().Err(err).Msg("Not deleting scene")
+				w.WriteHeader(http.StatusForbidden)
+			} else if err != nil {
 				log.Ctx(ctx).Warn().Err(err).Msg("Failed to delete scene")
 				w.WriteHeader(http.StatusInternalServerError)
 			}
 			return
 		}
 
-		go h.processUpdates(videoId, vdReq)
+		h.enqueueEdit(ctx, clientOf(req), videoId, vdReq)
 	}
 
 	vd, err := h.libraryService.GetScene(ctx, videoId, false)
@@ -110,47 +144,43 @@ func (h *httpHandler) videoDataHandler(w http.ResponseWriter, req *http.Request)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
-	dto, err := buildVideoData(ctx, vd, baseUrl)
+	dto, err := buildVideoData(ctx, vd, baseUrl, h.historyStore)
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("failed to build video data")
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	if !share.IsReadOnly(ctx) {
+		h.state.served.set(clientOf(req), vd)
+		addSplitTrack(&dto.Tags, h.state.feedback.take(videoId), nextTrack(dto.Tags), dto.Duration)
+	}
 
 	if err := internal.WriteJson(ctx, w, dto); err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("write")
 	}
 }
 
-func (h *httpHandler) processUpdates(videoId string, vdReq videoDataRequestDto) {
-	ctx := context.Background()
-	needsRefetch := false
-	if vdReq.Rating != nil {
-		if err := h.libraryService.UpdateRating(ctx, videoId, vdReq.Rating); err != nil {
-			log.Ctx(ctx).Warn().Err(err).Float32("rating", *vdReq.Rating).Msg("Failed to update rating")
-		}
-		needsRefetch = true
-	}
-	if vdReq.IsFavorite != nil {
-		if err := h.libraryService.UpdateFavorite(ctx, videoId, *vdReq.IsFavorite); err != nil {
-			log.Ctx(ctx).Warn().Err(err).Bool("isFavorite", *vdReq.IsFavorite).Msg("Failed to update favorite")
-		}
-		needsRefetch = true
-	}
-	if vdReq.Tags != nil {
-		h.processIncomingTags(ctx, videoId, vdReq)
-		needsRefetch = true
+func (h *httpHandler) isSceneAllowed(ctx context.Context, videoId string) bool {
+	if !share.IsReadOnly(ctx) {
+		return true
 	}
-	if needsRefetch {
-		_, err := h.libraryService.GetScene(ctx, videoId, true)
-		if err != nil {
-			log.Ctx(ctx).Warn().Err(err).Msg("Failed to refetch scene")
-		}
+	sections, err := h.libraryService.GetCachedSections(ctx)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to get sections")
+		return false
 	}
+	return share.Allows(ctx, sections, videoId)
 }
 
-func (h *httpHandler) processIncomingTags(ctx context.Context, videoId string, vdReq videoDataRequestDto) {
+// processIncomingTags applies the tags edited in HereSphere and returns tags to show the next time the scene is fetched,
+// from commands.
+func processIncomingTags(ctx context.Context, cs *library.ChangeSet, vdReq videoDataRequestDto, m mergeBase) []tagDto {
+	var feedbackTags []tagDto
 	newTags := make([]string, 0)
+	newPerformers := make([]string, 0)
+	newStudios := make([]string, 0)
+	newGroups := make([]string, 0)
+	newFields := make(map[library.Field][]string)
 	newMarkers := make([]library.MarkerDto, 0)
 
 	hasPlayCount := false
@@ -159,15 +189,42 @@ func (h *httpHandler) processIncomingTags(ctx context.Context, videoId string, v
 	hasRating := false
 
 	for _, t := range *vdReq.Tags {
+		if isCommand(t.Name) {
+			feedbackTags = append(feedbackTags, runCommand(ctx, cs, t.Name)...)
+			continue
+		}
+
 		key, arg, _ := strings.Cut(t.Name, ":")
 
 		if key == "" {
 			continue
 		}
 
+		if f, ok := fieldOf(key); ok {
+			if arg = singleLine(fieldValue(arg)); arg != "" {
+				newFields[f] = append(newFields[f], arg)
+			}
+			continue
+		}
+
 		switch key {
-		case internal.LegendPerformer, internal.LegendSceneStudio, internal.LegendSceneGroup,
-			internal.LegendMetaResolution, internal.LegendSummary, internal.LegendSummaryId:
+		case internal.LegendPerformer:
+			if arg != "" {
+				newPerformers = append(newPerformers, arg)
+			}
+			continue
+		case internal.LegendSceneStudio:
+			if arg != "" {
+				newStudios = append(newStudios, arg)
+			}
+			continue
+		case internal.LegendSceneGroup:
+			if arg != "" {
+				newGroups = append(newGroups, arg)
+			}
+			continue
+		case internal.LegendMetaResolution, internal.LegendMetaResume, internal.LegendSummary, internal.LegendSummaryId,
+			internal.LegendHelp, internal.LegendError:
 			continue
 		case internal.LegendMetaOCount:
 			hasOCount = true
@@ -190,69 +247,72 @@ func (h *httpHandler) processIncomingTags(ctx context.Context, videoId string, v
 			continue
 		}
 
-		if strings.EqualFold(key, internal.CommandIncrementO) {
-			if err := h.libraryService.IncrementO(ctx, videoId); err != nil {
-				log.Ctx(ctx).Warn().Err(err).Msg("Failed to increment O")
-			}
-			continue
-		}
-		if strings.EqualFold(key, internal.CommandSetOrganizedTrue) {
-			if err := h.libraryService.SetOrganized(ctx, videoId, true); err != nil {
-				log.Ctx(ctx).Warn().Err(err).Msg("Failed to set organized=true")
-			}
-			continue
-		}
-
-		m := library.MarkerDto{
+		marker := library.MarkerDto{
 			PrimaryTagName: key,
 			StartSecond:    t.Start / 1000,
 			MarkerId:       fmt.Sprintf("%.0f", *t.Rating),
 		}
 		if arg != "" {
-			m.Title = arg
+			marker.Title = arg
 		}
 		if t.End != nil {
-			m.EndSecond = util.Ptr(*t.End / 1000)
+			marker.EndSecond = util.Ptr(*t.End / 1000)
 		}
-		newMarkers = append(newMarkers, m)
+		newMarkers = append(newMarkers, marker)
 	}
 
-	if !hasPlayCount {
-		if err := h.libraryService.DecrementPlayCount(ctx, videoId); err != nil {
-			log.Ctx(ctx).Warn().Err(err).Msg("Failed to decrement play count")
+	// Pseudo-tags removed in HereSphere are only acted on if it's known they were served.
+	if m.known {
+		if !hasPlayCount && !m.conflicts(ctx, "play_count", m.current.PlayCount != m.served.PlayCount) {
+			cs.DecrementPlayCount()
 		}
-	}
-
-	if !hasOrganized {
-		if err := h.libraryService.SetOrganized(ctx, videoId, false); err != nil {
-			log.Ctx(ctx).Warn().Err(err).Msg("Failed to set organized=false")
+		if !hasOrganized && m.served.Organized && !m.conflicts(ctx, "organized", m.current.Organized != m.served.Organized) {
+			cs.SetOrganized(false)
 		}
-	}
-
-	if !hasOCount {
-		if err := h.libraryService.DecrementO(ctx, videoId); err != nil {
-			log.Ctx(ctx).Warn().Err(err).Msg("Failed to decrement O")
+		if !hasOCount && !m.conflicts(ctx, "o_counter", m.current.OCount != m.served.OCount) {
+			cs.DecrementO()
 		}
-	}
-
-	if !hasRating {
-		if err := h.libraryService.UpdateRating(ctx, videoId, nil); err != nil {
-			log.Ctx(ctx).Warn().Err(err).Msg("Failed to set zero rating")
+		if studio, edited := editedValue(m.served.Studio, newStudios); edited && studio == "" && !m.conflicts(ctx, "studio", m.current.Studio != m.served.Studio) {
+			cs.SetStudio("")
+		}
+		if !hasRating && m.served.Rating100 != nil && !m.conflicts(ctx, "rating", m.ratingChangedInStash()) {
+			cs.SetRating(nil)
 		}
 	}
 
-	if err := h.libraryService.UpdateTags(ctx, videoId, newTags); err != nil {
-		log.Ctx(ctx).Warn().Err(err).Msg("Failed to update tags")
+	cs.SetTags(mergeTags(m.served.Tags, cs.Tags(), newTags))
+	cs.SetPerformers(mergeTags(m.served.Performers, cs.Performers(), newPerformers))
+	if studio, edited := editedValue(m.served.Studio, newStudios); edited && studio != "" && !m.conflicts(ctx, "studio", m.current.Studio != m.served.Studio) {
+		cs.SetStudio(studio)
 	}
-
-	if err := h.libraryService.UpdateMarkers(ctx, videoId, newMarkers); err != nil {
-		log.Ctx(ctx).Warn().Err(err).Msg("Failed to update markers")
+	if !util.UnorderedEqual(newGroups, m.served.Groups) && !m.conflicts(ctx, "groups", !util.UnorderedEqual(m.current.Groups, m.served.Groups)) {
+		groups := make([]library.SceneGroup, 0, len(newGroups))
+		for _, arg := range mergeTags(m.served.Groups, groupArgs(cs.Groups()), newGroups) {
+			groups = append(groups, parseGroupArg(arg))
+		}
+		cs.SetGroups(groups)
 	}
+	for _, f := range library.Fields {
+		value, edited := editedValue(m.served.Fields[f], newFields[f])
+		if !edited || (value == "" && !m.known) || m.conflicts(ctx, string(f), m.current.Fields[f] != m.served.Fields[f]) {
+			continue
+		}
+		if err := cs.SetField(f, fieldValue(value)); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("Skipping field")
+			feedbackTags = append(feedbackTags, errorTag(err))
+		}
+	}
+	cs.MergeMarkers(ctx, newMarkers, m.served.Markers)
+	return feedbackTags
 }
 
 func (h *httpHandler) eventsHandler(w http.ResponseWriter, req *http.Request) {
 	ctx := req.Context()
 
+	if share.IsReadOnly(ctx) {
+		return
+	}
+
 	ev, err := internal.UnmarshalBody[playbackEvent](req)
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("failed to parse event body")
@@ -273,18 +333,13 @@ func (h *httpHandler) eventsHandler(w http.ResponseWriter, req *http.Request) {
 
 	switch ev.Event {
 	case evPlay:
-		if h.ps == nil {
-			h.ps = newPlayback(vd)
-		} else if h.ps.videoId != videoId {
-			h.ps.handleStop(ctx, h.libraryService, minPlayFraction)
-			h.ps = newPlayback(vd)
-		} else {
-			h.ps.handleResume()
-		}
-	case evPause, evClose:
-		if h.ps != nil {
-			h.ps.handleStop(ctx, h.libraryService, minPlayFraction)
-		}
+		h.playbackTracker.Play(ctx, vd, ev.toPlayback(req))
+	case evPause:
+		h.playbackTracker.Stop(ctx, videoId, ev.toPlayback(req))
+		saveResumeTime(ctx, h.libraryService, vd, ev)
+	case evClose:
+		h.playbackTracker.Close(ctx, videoId, ev.toPlayback(req))
+		saveResumeTime(ctx, h.libraryService, vd, ev)
 	default:
 	}
 }
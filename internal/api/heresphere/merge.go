@@ -0,0 +1,164 @@
+package heresphere
+
+import (
+	"context"
+	"github.com/rs/zerolog/log"
+	"slices"
+	"stash-vr/internal/library"
+	"stash-vr/internal/util"
+	"sync"
+	"time"
+)
+
+// servedState is the editable state of a scene as last served to HereSphere. HereSphere always sends back its full
+// list of tags, so edits are found by comparing against it and merged into the current state in Stash.
+type servedState struct {
+	UpdatedAt  time.Time                `json:"updatedAt"`
+	Tags       []string                 `json:"tags"`
+	Performers []string                 `json:"performers"`
+	Studio     string                   `json:"studio"`
+	Groups     []string                 `json:"groups"`
+	Fields     map[library.Field]string `json:"fields"`
+	Markers    []library.MarkerDto      `json:"markers"`
+	Rating100  *int                     `json:"rating100"`
+	PlayCount  int                      `json:"playCount"`
+	OCount     int                      `json:"oCount"`
+	Organized  bool                     `json:"organized"`
+}
+
+// servedStates holds the state last served to each client by scene, so that an edit is merged against what the client
+// that made it was served.
+type servedStates struct {
+	mu     sync.Mutex
+	states map[servedKey]servedState
+}
+
+type servedKey struct {
+	client  string
+	sceneId string
+}
+
+func newServedStates() *servedStates {
+	return &servedStates{states: make(map[servedKey]servedState)}
+}
+
+func (s *servedStates) set(client string, vd *library.VideoData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[servedKey{client: client, sceneId: vd.Id()}] = stateOf(vd)
+}
+
+func (s *servedStates) get(client string, id string) (servedState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.states[servedKey{client: client, sceneId: id}]
+	return state, ok
+}
+
+func stateOf(vd *library.VideoData) servedState {
+	state := servedState{
+		UpdatedAt: vd.SceneParts.Updated_at,
+		Rating100: vd.SceneParts.Rating100,
+		Organized: vd.SceneParts.Organized,
+	}
+	for _, t := range getStashTags(vd) {
+		state.Tags = append(state.Tags, t.value)
+	}
+	for _, p := range vd.SceneParts.Performers {
+		state.Performers = append(state.Performers, p.Name)
+	}
+	if vd.SceneParts.Studio != nil {
+		state.Studio = vd.SceneParts.Studio.Name
+	}
+	for _, g := range vd.Groups {
+		state.Groups = append(state.Groups, groupArg(g))
+	}
+	state.Fields = make(map[library.Field]string, len(library.Fields))
+	for _, f := range library.Fields {
+		state.Fields[f] = fieldArg(vd, f)
+	}
+	for _, sm := range vd.SceneParts.Scene_markers {
+		state.Markers = append(state.Markers, library.MarkerOf(sm))
+	}
+	if vd.SceneParts.Play_count != nil {
+		state.PlayCount = *vd.SceneParts.Play_count
+	}
+	if vd.SceneParts.O_counter != nil {
+		state.OCount = *vd.SceneParts.O_counter
+	}
+	return state
+}
+
+// mergeTags applies the tags added and removed in HereSphere since base to current. Tags HereSphere was never
+// served, like hidden tags or tags added in Stash meanwhile, are kept.
+func mergeTags(base []string, current []string, incoming []string) []string {
+	merged := make([]string, 0, len(current)+len(incoming))
+	for _, t := range current {
+		if slices.Contains(base, t) && !slices.Contains(incoming, t) {
+			continue
+		}
+		merged = append(merged, t)
+	}
+	for _, t := range incoming {
+		if !slices.Contains(base, t) && !slices.Contains(merged, t) {
+			merged = append(merged, t)
+		}
+	}
+	return merged
+}
+
+// editedValue returns the value of a single-valued tag, like the studio, set in HereSphere and whether it differs from
+// the one served, empty if it was removed. If values were added the last one wins, so that an untouched value is never
+// written back.
+func editedValue(served string, incoming []string) (string, bool) {
+	added := slices.DeleteFunc(slices.Clone(incoming), func(s string) bool {
+		return s == served
+	})
+	if len(added) > 0 {
+		return added[len(added)-1], true
+	}
+	if served != "" && !slices.Contains(incoming, served) {
+		return "", true
+	}
+	return served, false
+}
+
+func groupArgs(groups []library.SceneGroup) []string {
+	args := make([]string, len(groups))
+	for i, g := range groups {
+		args[i] = groupArg(g)
+	}
+	return args
+}
+
+// mergeBase is what edits from HereSphere are merged against.
+type mergeBase struct {
+	served servedState
+	// known is false if the scene wasn't served since startup, its current state is then used as served state.
+	known         bool
+	current       servedState
+	editedInStash bool
+}
+
+func newMergeBase(served servedState, known bool, current *library.VideoData) mergeBase {
+	m := mergeBase{served: served, known: known, current: stateOf(current)}
+	if !known {
+		m.served = m.current
+	}
+	m.editedInStash = !current.SceneParts.Updated_at.Equal(m.served.UpdatedAt)
+	return m
+}
+
+// conflicts reports whether a field edited in HereSphere was also changed in Stash since it was served, in which case
+// the edit is rejected.
+func (m mergeBase) conflicts(ctx context.Context, field string, changedInStash bool) bool {
+	if m.editedInStash && changedInStash {
+		log.Ctx(ctx).Warn().Str("field", field).Msg("Field was changed in Stash since it was served, rejecting conflicting edit")
+		return true
+	}
+	return false
+}
+
+func (m mergeBase) ratingChangedInStash() bool {
+	return !util.PtrEqual(m.served.Rating100, m.current.Rating100)
+}
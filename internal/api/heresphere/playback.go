@@ -3,54 +3,23 @@ package heresphere
 import (
 	"context"
 	"stash-vr/internal/library"
-	"time"
 
 	"github.com/rs/zerolog/log"
 )
 
-func newPlayback(vd *library.VideoData) *playbackState {
-	return &playbackState{
-		videoId:       vd.Id(),
-		videoDuration: vd.SceneParts.Files[0].Duration,
-		lastPlayTime:  time.Now(),
-		isPlaying:     true,
-	}
-}
+// resumeResetMarginSeconds is how close to the end a stop must be for the scene to count as finished,
+// in which case the resume point is cleared instead of saved.
+const resumeResetMarginSeconds = 5
 
-func (ps *playbackState) handleStop(ctx context.Context, libraryService *library.Service, minPlayFraction *float64) {
-	if ps.isPlaying {
-		currentPlayDuration := time.Since(ps.lastPlayTime)
-		ps.accumulatedPlayTime += currentPlayDuration
-		if !ps.thresholdReached && minPlayFraction != nil && ps.accumulatedPlayTime.Seconds() >= ps.videoDuration*(*minPlayFraction) {
-			ps.thresholdReached = true
-			log.Ctx(ctx).Debug().Str("total play time", ps.accumulatedPlayTime.Round(time.Second).String()).Msg("Incrementing play count")
-			err := libraryService.IncrementPlayCount(ctx, ps.videoId)
-			if err != nil {
-				log.Ctx(ctx).Warn().Err(err).Msg("Failed to increment play count")
-			}
-		}
-		log.Ctx(ctx).Debug().Str("duration", currentPlayDuration.Round(time.Second).String()).Msg("Adding play duration")
-		err := libraryService.AddPlayDuration(ctx, ps.videoId, currentPlayDuration)
-		if err != nil {
-			log.Ctx(ctx).Warn().Err(err).Msg("Failed to add play duration")
-		}
+func saveResumeTime(ctx context.Context, libraryService *library.Service, vd *library.VideoData, ev playbackEvent) {
+	position := ev.position()
+	duration := vd.SceneParts.Files[0].Duration
+	if position < 0 || position >= duration-resumeResetMarginSeconds {
+		position = 0
 	}
-	ps.isPlaying = false
-}
 
-func (ps *playbackState) handleResume() {
-	if !ps.isPlaying {
-		ps.lastPlayTime = time.Now()
+	log.Ctx(ctx).Debug().Float64("position", position).Msg("Saving resume time")
+	if err := libraryService.SaveResumeTime(ctx, vd.Id(), position); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("Failed to save resume time")
 	}
-	ps.isPlaying = true
-}
-
-type playbackState struct {
-	videoId       string
-	videoDuration float64
-
-	accumulatedPlayTime time.Duration
-	thresholdReached    bool
-	lastPlayTime        time.Time
-	isPlaying           bool
 }
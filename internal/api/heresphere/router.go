@@ -6,23 +6,26 @@ import (
 	"net/http"
 	"net/url"
 	"stash-vr/internal/api/internal"
+	"stash-vr/internal/edits"
+	"stash-vr/internal/history"
 	"stash-vr/internal/library"
+	"stash-vr/internal/playback"
 	"stash-vr/internal/static"
 )
 
-func Router(libraryService *library.Service) http.Handler {
-	httpHandler := httpHandler{libraryService: libraryService}
+func Router(libraryService *library.Service, playbackTracker *playback.Tracker, historyStore *history.Store, editQueue *edits.Queue, state *State) http.Handler {
+	httpHandler := httpHandler{libraryService: libraryService, playbackTracker: playbackTracker, historyStore: historyStore, editQueue: editQueue, state: state}
 	r := chi.NewRouter()
 	r.Use(middleware.SetHeader("HereSphere-JSON-Version", "1"))
-	r.Post("/", internal.LogRoute("index", httpHandler.indexHandler))
+	r.Post("/", internal.LogRoute("index", requireAuth(httpHandler.indexHandler)))
 	r.Get("/", internal.LogRoute("static", func(writer http.ResponseWriter, request *http.Request) {
 		http.ServeFileFS(writer, request, static.Fs, "loading.html")
 	}))
 
-	r.Post("/scan", internal.LogRoute("scan", httpHandler.scanHandler))
-	r.Post("/auth", http.NotFound)
-	r.Handle("/{videoId}", internal.LogRoute("videoData", internal.LogVideoId(httpHandler.videoDataHandler)))
-	r.Post("/events/{videoId}", internal.LogRoute("events", httpHandler.eventsHandler))
+	r.Post("/scan", internal.LogRoute("scan", requireAuth(httpHandler.scanHandler)))
+	r.Post("/auth", internal.LogRoute("auth", httpHandler.authHandler))
+	r.Handle("/{videoId}", internal.LogRoute("videoData", internal.LogVideoId(requireAuth(httpHandler.videoDataHandler))))
+	r.Post("/events/{videoId}", internal.LogRoute("events", requireAuth(httpHandler.eventsHandler)))
 	return r
 }
 
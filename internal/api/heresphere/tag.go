@@ -11,6 +11,7 @@ import (
 	"stash-vr/internal/util"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type tagDto struct {
@@ -24,6 +25,8 @@ type tagDto struct {
 
 const seperator = ":"
 
+const groupIndexSeparator = "#"
+
 var summaryStripper, _ = regexp.Compile("[^a-zA-Z0-9_]+")
 
 func setTrack(tag *tagDto, track int) {
@@ -68,6 +71,17 @@ func addSplitTrack(target *[]tagDto, tags []tagDto, track int, totalDuration flo
 	return track + 1
 }
 
+// nextTrack returns the first track not used by tags.
+func nextTrack(tags []tagDto) int {
+	track := 0
+	for _, t := range tags {
+		if t.Track != nil && *t.Track >= track {
+			track = *t.Track + 1
+		}
+	}
+	return track
+}
+
 func addMultiTracks(target *[]tagDto, tags []tagDto, startTrack int) int {
 	tagCount := len(tags)
 	if tagCount == 0 {
@@ -92,6 +106,7 @@ func getTags(vd *library.VideoData) []tagDto {
 	}
 
 	trackIndex = addSplitTrack(&tags, getFields(vd), trackIndex, duration)
+	trackIndex = addTrack(&tags, getResume(vd), trackIndex)
 	trackIndex = addMultiTracks(&tags, getStashTags(vd), trackIndex)
 	trackIndex = addMultiTracks(&tags, getStudio(vd), trackIndex)
 	trackIndex = addMultiTracks(&tags, getPerformers(vd), trackIndex)
@@ -198,18 +213,38 @@ func getPerformers(vd *library.VideoData) []tagDto {
 }
 
 func getGroups(vd *library.VideoData) []tagDto {
-	if vd.SceneParts.Groups == nil {
+	if vd.Groups == nil {
 		return nil
 	}
-	tags := make([]tagDto, len(vd.SceneParts.Groups))
-	for i, m := range vd.SceneParts.Groups {
+	tags := make([]tagDto, len(vd.Groups))
+	for i, g := range vd.Groups {
 		tags[i] = tagDto{
-			Name: fmt.Sprintf("%s%s%s", internal.LegendSceneGroup, seperator, m.Group.Name),
+			Name: fmt.Sprintf("%s%s%s", internal.LegendSceneGroup, seperator, groupArg(g)),
 		}
 	}
 	return tags
 }
 
+// groupArg returns the name of a group followed by the index of the scene in it, if set.
+func groupArg(g library.SceneGroup) string {
+	if g.SceneIndex == nil {
+		return g.Name
+	}
+	return fmt.Sprintf("%s%s%d", g.Name, groupIndexSeparator, *g.SceneIndex)
+}
+
+// parseGroupArg is the inverse of groupArg. A trailing #<number> is read as scene index, any other # as part of the
+// name.
+func parseGroupArg(arg string) library.SceneGroup {
+	i := strings.LastIndex(arg, groupIndexSeparator)
+	if i > 0 {
+		if index, err := strconv.Atoi(arg[i+len(groupIndexSeparator):]); err == nil && index >= 0 {
+			return library.SceneGroup{Name: arg[:i], SceneIndex: &index}
+		}
+	}
+	return library.SceneGroup{Name: arg}
+}
+
 func getStudio(vd *library.VideoData) []tagDto {
 	if vd.SceneParts.Studio == nil {
 		return nil
@@ -248,9 +283,80 @@ func getFields(vd *library.VideoData) []tagDto {
 		tags = append(tags, tagDto{Name: fmt.Sprintf("%s%s%v", internal.LegendMetaInteractive, seperator, vd.SceneParts.Interactive)})
 	}
 
+	for _, f := range library.Fields {
+		if arg := fieldArg(vd, f); arg != "" {
+			tags = append(tags, tagDto{Name: fmt.Sprintf("%s%s%s", fieldLegends[f], seperator, arg)})
+		}
+	}
+
 	return tags
 }
 
+// fieldLegends are the keys of the tags of the editable text fields of a scene.
+var fieldLegends = map[library.Field]string{
+	library.FieldTitle:   internal.LegendMetaTitle,
+	library.FieldDate:    internal.LegendMetaDate,
+	library.FieldCode:    internal.LegendMetaCode,
+	library.FieldDetails: internal.LegendMetaDetails,
+}
+
+func fieldOf(key string) (library.Field, bool) {
+	for f, legend := range fieldLegends {
+		if legend == key {
+			return f, true
+		}
+	}
+	return "", false
+}
+
+// lineBreakMark stands for a line break in text fields shown as tags.
+const lineBreakMark = "¶"
+
+// fieldArg returns a text field of the scene on a single line, as tags can't span lines.
+func fieldArg(vd *library.VideoData, f library.Field) string {
+	return singleLine(vd.Field(f))
+}
+
+// singleLine joins the lines of text with lineBreakMark, collapsing other whitespace.
+func singleLine(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.Join(lines, " "+lineBreakMark+" ")
+}
+
+// fieldValue returns the text of a field edited in HereSphere, with line breaks restored.
+func fieldValue(arg string) string {
+	lines := strings.Split(arg, lineBreakMark)
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func getResume(vd *library.VideoData) []tagDto {
+	if vd.SceneParts.Resume_time == nil || *vd.SceneParts.Resume_time <= 0 {
+		return nil
+	}
+	resumeTime := *vd.SceneParts.Resume_time
+	return []tagDto{{
+		Name:  fmt.Sprintf("%s%s%s", internal.LegendMetaResume, seperator, formatPosition(resumeTime)),
+		Start: resumeTime * 1000,
+	}}
+}
+
+func formatPosition(seconds float64) string {
+	d := time.Duration(seconds) * time.Second
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	s := int(d.Seconds()) % 60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", m, s)
+}
+
 func getMarkers(vd *library.VideoData) []tagDto {
 	tags := make([]tagDto, len(vd.SceneParts.Scene_markers))
 	for i, sm := range vd.SceneParts.Scene_markers {
@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"stash-vr/internal/api/heatmap"
 	"stash-vr/internal/api/internal"
+	"stash-vr/internal/api/media"
 	"stash-vr/internal/config"
+	"stash-vr/internal/history"
 	"stash-vr/internal/library"
+	"stash-vr/internal/share"
 	"stash-vr/internal/stash"
 	"stash-vr/internal/util"
 	"time"
@@ -64,33 +67,30 @@ type subtitleDto struct {
 	Url      string `json:"url,omitempty"`
 }
 
-func buildVideoData(ctx context.Context, vd *library.VideoData, baseUrl string) (*videoDataDto, error) {
+func buildVideoData(ctx context.Context, vd *library.VideoData, baseUrl string, historyStore *history.Store) (*videoDataDto, error) {
 	videoId := vd.Id()
 	if len(vd.SceneParts.Files) == 0 {
 		return nil, fmt.Errorf("scene %s has no files", videoId)
 	}
 
+	writable := !share.IsReadOnly(ctx)
 	dto := videoDataDto{
-		Access:        1,
+		Access:        accessGranted,
 		Title:         vd.Title(),
 		DateAdded:     vd.SceneParts.Created_at.Format(time.DateOnly),
 		Duration:      vd.SceneParts.Files[0].Duration * 1000,
-		WriteFavorite: util.Ptr(true),
-		WriteRating:   util.Ptr(true),
-		WriteTags:     util.Ptr(true),
-		EventServer:   util.Ptr(getEventsUrl(baseUrl, videoId)),
+		WriteFavorite: util.Ptr(writable && config.Application().CanWrite(config.WriteFavorite)),
+		WriteRating:   util.Ptr(writable && config.Application().CanWrite(config.WriteRating)),
+		WriteTags:     util.Ptr(writable && config.Application().CanWrite(config.WriteTags)),
 	}
-
-	if vd.SceneParts.Paths.Screenshot != nil {
-		if vd.SceneParts.Interactive && vd.SceneParts.Paths.Interactive_heatmap != nil {
-			dto.ThumbnailImage = util.Ptr(heatmap.GetCoverUrl(baseUrl, videoId))
-		} else {
-			dto.ThumbnailImage = util.Ptr(stash.ApiKeyed(*vd.SceneParts.Paths.Screenshot))
-		}
+	if writable {
+		dto.EventServer = util.Ptr(getEventsUrl(baseUrl, videoId))
 	}
 
+	dto.ThumbnailImage = heatmap.GetThumbnailUrl(ctx, baseUrl, vd, historyStore)
+
 	if vd.SceneParts.Paths.Preview != nil {
-		dto.ThumbnailVideo = util.Ptr(stash.ApiKeyed(*vd.SceneParts.Paths.Preview))
+		dto.ThumbnailVideo = util.Ptr(media.Url(ctx, baseUrl, videoId, media.KindPreview, *vd.SceneParts.Paths.Preview))
 	}
 
 	if vd.SceneParts.Date != nil {
@@ -113,13 +113,13 @@ func buildVideoData(ctx context.Context, vd *library.VideoData, baseUrl string)
 		dto.IsFavorite = util.Ptr(true)
 	}
 
-	setMediaSources(vd, &dto)
+	setMediaSources(ctx, vd, &dto, baseUrl)
 
 	set3DFormat(vd, &dto)
 
-	setScripts(vd, &dto)
+	setScripts(ctx, vd, &dto, baseUrl)
 
-	setSubtitles(vd, &dto)
+	setSubtitles(ctx, vd, &dto, baseUrl)
 
 	dto.Tags = getTags(vd)
 
@@ -132,7 +132,7 @@ func buildVideoData(ctx context.Context, vd *library.VideoData, baseUrl string)
 	return &dto, nil
 }
 
-func setSubtitles(vd *library.VideoData, dto *videoDataDto) {
+func setSubtitles(ctx context.Context, vd *library.VideoData, dto *videoDataDto, baseUrl string) {
 	if vd.SceneParts.Captions == nil {
 		return
 	}
@@ -140,7 +140,7 @@ func setSubtitles(vd *library.VideoData, dto *videoDataDto) {
 		dto.Subtitles = append(dto.Subtitles, subtitleDto{
 			Name:     fmt.Sprintf("%s.%s", c.Language_code, c.Caption_type),
 			Language: c.Language_code,
-			Url:      stash.ApiKeyed(fmt.Sprintf("%s?lang=%s&type=%s", *vd.SceneParts.Paths.Caption, c.Language_code, c.Caption_type)),
+			Url:      media.Url(ctx, baseUrl, vd.Id(), media.KindCaption, fmt.Sprintf("%s?lang=%s&type=%s", *vd.SceneParts.Paths.Caption, c.Language_code, c.Caption_type)),
 		})
 	}
 }
@@ -154,13 +154,13 @@ func isFavorite(vd *library.VideoData) bool {
 	return false
 }
 
-func setScripts(vd *library.VideoData, dto *videoDataDto) {
+func setScripts(ctx context.Context, vd *library.VideoData, dto *videoDataDto, baseUrl string) {
 	if !vd.SceneParts.Interactive {
 		return
 	}
 	dto.Scripts = append(dto.Scripts, scriptDto{
 		Name: "Script-" + vd.Title(),
-		Url:  stash.ApiKeyed(*vd.SceneParts.Paths.Funscript),
+		Url:  media.Url(ctx, baseUrl, vd.Id(), media.KindFunscript, *vd.SceneParts.Paths.Funscript),
 	})
 }
 
@@ -206,16 +206,17 @@ func set3DFormat(vd *library.VideoData, dto *videoDataDto) {
 	}
 }
 
-func setMediaSources(vd *library.VideoData, dto *videoDataDto) {
+func setMediaSources(ctx context.Context, vd *library.VideoData, dto *videoDataDto, baseUrl string) {
 	streams := []stash.Stream{stash.GetDirectStream(vd.SceneParts), stash.GetTranscodingStream(vd.SceneParts)}
-	for _, stream := range streams {
+	kinds := []media.Kind{media.KindStream, media.KindTranscode}
+	for i, stream := range streams {
 		e := mediaDto{
 			Name: stream.Name,
 		}
 		for _, s := range stream.Sources {
 			vs := sourceDto{
 				Resolution: s.Resolution,
-				Url:        s.Url,
+				Url:        media.Url(ctx, baseUrl, vd.Id(), kinds[i], s.Url),
 			}
 			e.Sources = append(e.Sources, vs)
 		}
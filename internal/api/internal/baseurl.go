@@ -1,11 +1,21 @@
 package internal
 
 import (
+	"context"
 	"net/http"
 	"stash-vr/internal/util"
 )
 
+type ctxKeyBasePath struct{}
+
 func GetBaseUrl(req *http.Request) string {
 	scheme := util.GetScheme(req)
-	return scheme + "://" + req.Host
+	basePath, _ := req.Context().Value(ctxKeyBasePath{}).(string)
+	return scheme + "://" + req.Host + basePath
+}
+
+// WithBasePath makes urls built by GetBaseUrl for this request start with basePath, e.g. to keep a device token
+// in all links handed to the player.
+func WithBasePath(ctx context.Context, basePath string) context.Context {
+	return context.WithValue(ctx, ctxKeyBasePath{}, basePath)
 }
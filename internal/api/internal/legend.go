@@ -13,12 +13,17 @@ var (
 	LegendMetaResolution  = "Resolution"
 	LegendMetaRating      = "Rating"
 	LegendMetaInteractive = "Interactive"
+	LegendMetaResume      = "Resume"
+	LegendMetaTitle       = "Title"
+	LegendMetaDate        = "Date"
+	LegendMetaCode        = "Code"
+	LegendMetaDetails     = "Details"
 
 	LegendSummary   = "Summary"
 	LegendSummaryId = "SummaryId"
 
-	CommandIncrementO       = "/o"
-	CommandSetOrganizedTrue = "/org"
+	LegendHelp  = "Help"
+	LegendError = "Error"
 )
 
 var (
@@ -0,0 +1,107 @@
+package media
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"stash-vr/internal/api/internal"
+	"stash-vr/internal/library"
+	"strings"
+)
+
+// Router serves scene resources from Stash to urls signed by Url, so that players never see the Stash host or api
+// key. Range requests are passed through as is to support seeking.
+func Router(libraryService *library.Service) http.Handler {
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.Out.URL = pr.In.Context().Value(ctxKeyUpstream{}).(*url.URL)
+			pr.Out.Host = pr.Out.URL.Host
+			pr.Out.Header.Del("Authorization")
+			pr.Out.Header.Del("Cookie")
+		},
+		Transport: libraryService.HttpClient.Transport,
+		ModifyResponse: func(resp *http.Response) error {
+			resp.Header.Del("Set-Cookie")
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, req *http.Request, err error) {
+			log.Ctx(req.Context()).Warn().Err(err).Msg("Media proxy request to Stash failed")
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	}
+
+	r := chi.NewRouter()
+	r.Method(http.MethodGet, "/{sceneId}/{kind}", handler(libraryService, proxy))
+	r.Method(http.MethodHead, "/{sceneId}/{kind}", handler(libraryService, proxy))
+	return r
+}
+
+type ctxKeyUpstream struct{}
+
+func handler(libraryService *library.Service, proxy *httputil.ReverseProxy) http.Handler {
+	return internal.LogRoute("media", func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		sceneId := chi.URLParam(req, "sceneId")
+		kind := Kind(chi.URLParam(req, "kind"))
+
+		forward, ok := verify(sceneId, kind, req.URL.Query())
+		if !ok {
+			log.Ctx(ctx).Debug().Str("sceneId", sceneId).Str("kind", string(kind)).Msg("Invalid or expired media url")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		vd, err := libraryService.GetScene(ctx, sceneId, false)
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("sceneId", sceneId).Msg("Failed to get scene")
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		upstream, ok := upstreamUrl(vd, kind)
+		if !ok {
+			log.Ctx(ctx).Debug().Str("sceneId", sceneId).Str("kind", string(kind)).Msg("Scene has no such media")
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		upstream.RawQuery = forward.Encode()
+
+		proxy.ServeHTTP(w, req.WithContext(context.WithValue(ctx, ctxKeyUpstream{}, upstream)))
+	})
+}
+
+// upstreamUrl returns the Stash url, without query, of a scene resource.
+func upstreamUrl(vd *library.VideoData, kind Kind) (*url.URL, bool) {
+	p := vd.SceneParts.Paths
+	var raw *string
+	switch kind {
+	case KindScreenshot:
+		raw = p.Screenshot
+	case KindPreview:
+		raw = p.Preview
+	case KindStream:
+		raw = p.Stream
+	case KindFunscript:
+		raw = p.Funscript
+	case KindCaption:
+		raw = p.Caption
+	case KindTranscode:
+		for _, s := range vd.SceneParts.SceneStreams {
+			if s.Mime_type != nil && strings.HasPrefix(*s.Mime_type, "video/mp4") && (s.Label == nil || *s.Label != "Direct stream") {
+				raw = &s.Url
+				break
+			}
+		}
+	}
+	if raw == nil {
+		return nil, false
+	}
+	u, err := url.Parse(*raw)
+	if err != nil {
+		return nil, false
+	}
+	u.RawQuery = ""
+	return u, true
+}
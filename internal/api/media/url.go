@@ -0,0 +1,134 @@
+package media
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"stash-vr/internal/config"
+	"stash-vr/internal/share"
+	"stash-vr/internal/stash"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Kind is a scene resource that can be served by the proxy.
+type Kind string
+
+const (
+	KindScreenshot Kind = "screenshot"
+	KindPreview    Kind = "preview"
+	KindStream     Kind = "stream"
+	KindTranscode  Kind = "transcode"
+	KindFunscript  Kind = "funscript"
+	KindCaption    Kind = "caption"
+)
+
+const (
+	paramExpires   = "exp"
+	paramSignature = "sig"
+)
+
+const (
+	keyFile = "media.key"
+	keySize = 32
+)
+
+// signingKey signs proxy urls. LoadKey replaces it with the key saved to CONFIG_PATH so that urls keep working after a
+// restart, without CONFIG_PATH it's created on start and players fetch new urls along with video data.
+var signingKey = func() []byte {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+	return key
+}()
+
+// LoadKey reads the signing key from CONFIG_PATH, saving the current one if there is none. It must be called before
+// serving requests.
+func LoadKey(ctx context.Context) {
+	if config.Application().ConfigPath == "" {
+		return
+	}
+	path := filepath.Join(config.Application().ConfigPath, keyFile)
+	key, err := os.ReadFile(path)
+	if err == nil && len(key) == keySize {
+		signingKey = key
+		return
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to read media key")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("error creating config directory")
+		return
+	}
+	if err := os.WriteFile(path, signingKey, 0o600); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to save media key")
+	}
+}
+
+// Url returns the url a player should use for a scene resource located at stashUrl. With MEDIA_PROXY enabled, or for
+// requests made with a share link so that guests never get the api key, it's a signed url to the proxy. Otherwise it's
+// the Stash url with the api key appended.
+func Url(ctx context.Context, baseUrl string, sceneId string, kind Kind, stashUrl string) string {
+	if !config.Application().MediaProxy && !share.IsReadOnly(ctx) {
+		return stash.ApiKeyed(stashUrl)
+	}
+
+	q := url.Values{}
+	if u, err := url.Parse(stashUrl); err == nil {
+		q = u.Query()
+		q.Del("apikey")
+	}
+	q.Set(paramExpires, strconv.FormatInt(time.Now().Add(ttl(kind)).Unix(), 10))
+	q.Set(paramSignature, sign(sceneId, kind, q))
+	return baseUrl + "/media/" + url.PathEscape(sceneId) + "/" + string(kind) + "?" + q.Encode()
+}
+
+// ttl returns how long a url for kind stays valid. Videos are requested in ranges for as long as they are open, so
+// their urls must outlive a viewing.
+func ttl(kind Kind) time.Duration {
+	switch kind {
+	case KindStream, KindTranscode:
+		return max(config.Application().MediaUrlTTL, config.Application().MediaStreamUrlTTL)
+	}
+	return config.Application().MediaUrlTTL
+}
+
+// verify checks the signature and expiry of a proxy url and returns the query parameters to forward to Stash.
+func verify(sceneId string, kind Kind, q url.Values) (url.Values, bool) {
+	signature := q.Get(paramSignature)
+	q = cloneValues(q)
+	q.Del(paramSignature)
+	if !hmac.Equal([]byte(signature), []byte(sign(sceneId, kind, q))) {
+		return nil, false
+	}
+	expires, err := strconv.ParseInt(q.Get(paramExpires), 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return nil, false
+	}
+	q.Del(paramExpires)
+	return q, true
+}
+
+func sign(sceneId string, kind Kind, q url.Values) string {
+	mac := hmac.New(sha256.New, signingKey)
+	mac.Write([]byte(sceneId + "/" + string(kind) + "?" + q.Encode()))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func cloneValues(q url.Values) url.Values {
+	out := make(url.Values, len(q))
+	for k, v := range q {
+		out[k] = v
+	}
+	return out
+}
@@ -1,22 +1,32 @@
 package api
 
 import (
+	"context"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/rs/zerolog/log"
 	"net/http"
+	"regexp"
+	"stash-vr/internal/api/auth"
 	"stash-vr/internal/api/deovr"
 	"stash-vr/internal/api/heatmap"
 	"stash-vr/internal/api/heresphere"
+	"stash-vr/internal/api/media"
 	"stash-vr/internal/api/web"
+	"stash-vr/internal/audit"
 	"stash-vr/internal/config"
+	"stash-vr/internal/device"
+	"stash-vr/internal/edits"
+	"stash-vr/internal/history"
 	"stash-vr/internal/library"
+	"stash-vr/internal/playback"
+	"stash-vr/internal/share"
 	"stash-vr/internal/static"
 	"stash-vr/internal/util"
 	"time"
 )
 
-func Router(libraryService *library.Service) *chi.Mux {
+func Router(ctx context.Context, libraryService *library.Service, playbackTracker *playback.Tracker, historyStore *history.Store, deviceStore *device.Store, shareSigner *share.Signer, editQueue *edits.Queue, heresphereState *heresphere.State, auditLog *audit.Log) *chi.Mux {
 	router := chi.NewRouter()
 
 	router.Use(requestLogger)
@@ -25,13 +35,57 @@ func Router(libraryService *library.Service) *chi.Mux {
 
 	//router.Mount("/debug", middleware.Profiler())
 
-	router.Mount("/heresphere", logMod("heresphere", heresphere.Router(libraryService)))
-	router.Mount("/deovr", logMod("deovr", deovr.Router(libraryService)))
+	router.With(auth.Middleware(ctx, config.AuthGroupHeresphere)).
+		Mount("/heresphere", logMod("heresphere", heresphere.Router(libraryService, playbackTracker, historyStore, editQueue, heresphereState)))
+	router.With(auth.Middleware(ctx, config.AuthGroupDeovr)).
+		Mount("/deovr", logMod("deovr", deovr.Router(libraryService, historyStore)))
 
-	router.Post("/filters", logMod("filters", web.FiltersUpdateHandler()).ServeHTTP)
-	router.Get("/cover/{videoId}", logMod("heatmap", heatmap.CoverHandler(libraryService)).ServeHTTP)
+	router.With(auth.Middleware(ctx, config.AuthGroupCover)).
+		Get("/cover/{videoId}", logMod("heatmap", heatmap.CoverHandler(libraryService, historyStore)).ServeHTTP)
 
-	router.Get("/", web.IndexHandler(libraryService).ServeHTTP)
+	// The device token is what authorizes paired devices, AUTH_* only apply to the routes without one.
+	router.Route("/d/{"+auth.DeviceTokenParam+"}", func(r chi.Router) {
+		r.Use(auth.DeviceMiddleware(deviceStore))
+		r.Mount("/heresphere", logMod("heresphere", heresphere.Router(libraryService, playbackTracker, historyStore, editQueue, heresphereState)))
+		r.Mount("/deovr", logMod("deovr", deovr.Router(libraryService, historyStore)))
+		r.Get("/cover/{videoId}", logMod("heatmap", heatmap.CoverHandler(libraryService, historyStore)).ServeHTTP)
+		r.Mount("/media", logMod("media", media.Router(libraryService)))
+	})
+	router.Route("/s/{"+auth.ShareTokenParam+"}", func(r chi.Router) {
+		r.Use(auth.ShareMiddleware(shareSigner))
+		r.Mount("/heresphere", logMod("heresphere", heresphere.Router(libraryService, playbackTracker, historyStore, editQueue, heresphereState)))
+		r.Mount("/deovr", logMod("deovr", deovr.Router(libraryService, historyStore)))
+		r.Get("/cover/{videoId}", logMod("heatmap", heatmap.CoverHandler(libraryService, historyStore)).ServeHTTP)
+		r.Mount("/media", logMod("media", media.Router(libraryService)))
+	})
+	router.Get("/ca.crt", web.CAHandler().ServeHTTP)
+	router.Get("/pair/{code}", logMod("devices", web.PairHandler(deviceStore)).ServeHTTP)
+
+	// Media urls are signed and short-lived, the signature is what authorizes them.
+	router.Mount("/media", logMod("media", media.Router(libraryService)))
+
+	router.Group(func(r chi.Router) {
+		r.Use(auth.Middleware(ctx, config.AuthGroupWeb))
+		r.Use(auth.SameOrigin)
+		r.Post("/devices/pair", logMod("devices", web.DevicePairingCreateHandler(deviceStore)).ServeHTTP)
+		r.Get("/devices/pair/{code}/qr.png", logMod("devices", web.DevicePairingQrHandler()).ServeHTTP)
+		r.Post("/devices/{deviceId}/revoke", logMod("devices", web.DeviceRevokeHandler(deviceStore)).ServeHTTP)
+		r.Post("/shares", logMod("shares", web.ShareCreateHandler(shareSigner)).ServeHTTP)
+		r.Post("/shares/revoke", logMod("shares", web.ShareRevokeAllHandler(shareSigner)).ServeHTTP)
+		r.Post("/shares/{shareId}/revoke", logMod("shares", web.ShareRevokeHandler(shareSigner)).ServeHTTP)
+		r.Post("/edits/{editId}/retry", logMod("edits", web.EditRetryHandler(editQueue)).ServeHTTP)
+		r.Post("/edits/{editId}/discard", logMod("edits", web.EditDiscardHandler(editQueue)).ServeHTTP)
+		r.Post("/filters", logMod("filters", web.FiltersUpdateHandler()).ServeHTTP)
+		r.Post("/trash/{sceneId}/restore", logMod("trash", web.TrashRestoreHandler(libraryService)).ServeHTTP)
+		r.Post("/trash/{sceneId}/purge", logMod("trash", web.TrashPurgeHandler(libraryService)).ServeHTTP)
+		r.Get("/audit", logMod("audit", web.AuditHandler(auditLog)).ServeHTTP)
+		r.Post("/audit/{entryId}/revert", logMod("audit", web.AuditRevertHandler(libraryService)).ServeHTTP)
+		r.Post("/audit/sessions/{sessionId}/revert", logMod("audit", web.AuditSessionRevertHandler(libraryService)).ServeHTTP)
+		r.Get("/stats", logMod("stats", web.StatsHandler(historyStore)).ServeHTTP)
+		r.Get("/stats/history.csv", logMod("stats", web.HistoryCsvHandler(historyStore)).ServeHTTP)
+		r.Get("/stats/history.json", logMod("stats", web.HistoryJsonHandler(historyStore)).ServeHTTP)
+		r.Get("/", web.IndexHandler(libraryService, deviceStore, shareSigner, editQueue).ServeHTTP)
+	})
 
 	router.Get("/*", http.FileServerFS(static.Fs).ServeHTTP)
 
@@ -45,10 +99,12 @@ func logMod(value string, next http.Handler) http.Handler {
 	})
 }
 
+var tokenPathPattern = regexp.MustCompile(`^/([ds])/[^/?]+`)
+
 func requestLogger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		scheme := util.GetScheme(r)
-		url := scheme + "://" + config.Redacted(r.Host) + r.RequestURI
+		url := scheme + "://" + config.Redacted(r.Host) + tokenPathPattern.ReplaceAllString(r.RequestURI, "/$1/REDACTED")
 
 		baseLogger := log.Ctx(r.Context()).With().
 			Str("method", r.Method).
@@ -0,0 +1,137 @@
+package web
+
+import (
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+	"html/template"
+	"net/http"
+	"slices"
+	"stash-vr/internal/audit"
+	"stash-vr/internal/config"
+	"stash-vr/internal/library"
+	"stash-vr/internal/static"
+	"time"
+)
+
+var auditTmpl = template.Must(template.ParseFS(static.Fs, "audit.gohtml"))
+
+// auditPageSize is how many of the newest entries are listed.
+const auditPageSize = 500
+
+const webSource = "Web UI"
+
+type auditEntryData struct {
+	Id         string
+	Time       string
+	SceneId    string
+	Source     string
+	Session    string
+	Kind       string
+	Before     string
+	After      string
+	Reverts    string
+	RevertedBy string
+	CanRevert  bool
+}
+
+type auditSessionData struct {
+	Id        string
+	Source    string
+	Start     string
+	End       string
+	Changes   int
+	CanRevert bool
+}
+
+type auditData struct {
+	Entries     []auditEntryData
+	Sessions    []auditSessionData
+	Total       int
+	IsPersisted bool
+}
+
+func buildAuditData(auditLog *audit.Log) auditData {
+	entries := auditLog.Entries()
+	data := auditData{Total: len(entries), IsPersisted: config.Application().ConfigPath != ""}
+
+	sessions := make(map[string]*auditSessionData)
+	for _, e := range slices.Backward(entries) {
+		revertedBy, reverted := auditLog.RevertedBy(e.Id)
+		canRevert := !reverted && e.Reverts == "" && e.IsRevertible()
+
+		s, ok := sessions[e.Session]
+		if !ok {
+			s = &auditSessionData{Id: e.Session, Source: e.Source, End: e.Time.Local().Format(time.DateTime)}
+			sessions[e.Session] = s
+			data.Sessions = append(data.Sessions, *s)
+		}
+		s.Start = e.Time.Local().Format(time.DateTime)
+		s.Changes++
+		s.CanRevert = s.CanRevert || canRevert
+
+		if len(data.Entries) < auditPageSize {
+			data.Entries = append(data.Entries, auditEntryData{
+				Id:         e.Id,
+				Time:       e.Time.Local().Format(time.DateTime),
+				SceneId:    e.SceneId,
+				Source:     e.Source,
+				Session:    e.Session,
+				Kind:       string(e.Kind),
+				Before:     string(e.Before),
+				After:      string(e.After),
+				Reverts:    e.Reverts,
+				RevertedBy: revertedBy,
+				CanRevert:  canRevert,
+			})
+		}
+	}
+	for i, s := range data.Sessions {
+		data.Sessions[i] = *sessions[s.Id]
+	}
+	return data
+}
+
+func AuditHandler(auditLog *audit.Log) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := auditTmpl.Execute(w, buildAuditData(auditLog)); err != nil {
+			log.Ctx(r.Context()).Err(err).Msg("audit: execute template")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+func AuditRevertHandler(libraryService *library.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := audit.WithSource(r.Context(), webSource)
+		id := chi.URLParam(r, "entryId")
+		if err := libraryService.Revert(ctx, id); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("entryId", id).Msg("Failed to revert change")
+			writeRevertError(w, err)
+			return
+		}
+		http.Redirect(w, r, "/audit", http.StatusSeeOther)
+	}
+}
+
+func AuditSessionRevertHandler(libraryService *library.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := audit.WithSource(r.Context(), webSource)
+		id := chi.URLParam(r, "sessionId")
+		if err := libraryService.RevertSession(ctx, id); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("sessionId", id).Msg("Failed to revert session")
+			writeRevertError(w, err)
+			return
+		}
+		log.Ctx(ctx).Info().Str("sessionId", id).Msg("Session reverted")
+		http.Redirect(w, r, "/audit", http.StatusSeeOther)
+	}
+}
+
+func writeRevertError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, library.ErrNotRevertible) {
+		status = http.StatusConflict
+	}
+	http.Error(w, err.Error(), status)
+}
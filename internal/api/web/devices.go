@@ -0,0 +1,156 @@
+package web
+
+import (
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/skip2/go-qrcode"
+	"html/template"
+	"net"
+	"net/http"
+	"slices"
+	"stash-vr/internal/api/auth"
+	"stash-vr/internal/api/internal"
+	"stash-vr/internal/device"
+	"stash-vr/internal/static"
+	"strings"
+	"time"
+)
+
+var pairTmpl = template.Must(template.ParseFS(static.Fs, "pair.gohtml"))
+
+type deviceData struct {
+	Id        string
+	Name      string
+	CreatedAt string
+}
+
+type pendingPairingData struct {
+	Code    string
+	Name    string
+	Url     string
+	QrUrl   string
+	Expires string
+}
+
+type pairData struct {
+	Error         string
+	DeviceName    string
+	HeresphereUrl string
+	DeovrUrl      string
+}
+
+func devicesData(deviceStore *device.Store) []deviceData {
+	devices := deviceStore.Devices()
+	out := make([]deviceData, len(devices))
+	for i, d := range devices {
+		out[i] = deviceData{Id: d.Id, Name: d.Name, CreatedAt: d.CreatedAt.Local().Format(time.DateTime)}
+	}
+	return out
+}
+
+func pendingPairingsData(req *http.Request, deviceStore *device.Store) []pendingPairingData {
+	baseUrl := internal.GetBaseUrl(req)
+	pending := deviceStore.PendingCodes()
+	slices.SortFunc(pending, func(a, b device.Pending) int {
+		return a.Expires.Compare(b.Expires)
+	})
+	out := make([]pendingPairingData, len(pending))
+	for i, p := range pending {
+		out[i] = pendingPairingData{
+			Code:    p.Code,
+			Name:    p.Name,
+			Url:     getPairUrl(baseUrl, p.Code),
+			QrUrl:   "/devices/pair/" + p.Code + "/qr.png",
+			Expires: p.Expires.Local().Format(time.TimeOnly),
+		}
+	}
+	return out
+}
+
+func getPairUrl(baseUrl string, code string) string {
+	return baseUrl + "/pair/" + code
+}
+
+func DevicePairingCreateHandler(deviceStore *device.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "bad form", 400)
+			return
+		}
+		name := strings.TrimSpace(r.PostForm.Get("name"))
+		if _, err := deviceStore.NewPairingCode(name); err != nil {
+			log.Ctx(r.Context()).Err(err).Msg("Failed to create pairing code")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		http.Redirect(w, r, "/#devices", http.StatusSeeOther)
+	}
+}
+
+func DevicePairingQrHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		code := chi.URLParam(r, "code")
+		png, err := qrcode.Encode(getPairUrl(internal.GetBaseUrl(r), code), qrcode.Medium, 256)
+		if err != nil {
+			log.Ctx(r.Context()).Err(err).Msg("Failed to encode QR code")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		if _, err := w.Write(png); err != nil {
+			log.Ctx(r.Context()).Err(err).Msg("qr: write")
+		}
+	}
+}
+
+func DeviceRevokeHandler(deviceStore *device.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "deviceId")
+		if !deviceStore.Revoke(r.Context(), id) {
+			http.NotFound(w, r)
+			return
+		}
+		log.Ctx(r.Context()).Info().Str("deviceId", id).Msg("Device revoked")
+		http.Redirect(w, r, "/#devices", http.StatusSeeOther)
+	}
+}
+
+// PairHandler is opened by the headset to exchange a pairing code for links to the library that include a device
+// token.
+func PairHandler(deviceStore *device.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		code := chi.URLParam(r, "code")
+		client, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			client = r.RemoteAddr
+		}
+
+		var data pairData
+		d, token, err := deviceStore.Pair(ctx, code, client, r.UserAgent())
+		if err != nil {
+			if errors.Is(err, device.ErrInvalidCode) {
+				log.Ctx(ctx).Debug().Str("remoteAddr", r.RemoteAddr).Msg("Invalid pairing code")
+				w.WriteHeader(http.StatusNotFound)
+			} else if errors.Is(err, device.ErrTooManyAttempts) {
+				log.Ctx(ctx).Warn().Str("remoteAddr", r.RemoteAddr).Msg("Pairing attempt rejected, too many invalid codes")
+				w.WriteHeader(http.StatusTooManyRequests)
+			} else {
+				log.Ctx(ctx).Err(err).Msg("Failed to pair device")
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			data.Error = err.Error()
+		} else {
+			log.Ctx(ctx).Info().Str("deviceId", d.Id).Str("name", d.Name).Msg("Device paired")
+			deviceUrl := internal.GetBaseUrl(r) + auth.DevicePath(token)
+			data.DeviceName = d.Name
+			data.HeresphereUrl = deviceUrl + "/heresphere"
+			data.DeovrUrl = deviceUrl + "/deovr"
+		}
+
+		if err := pairTmpl.Execute(w, data); err != nil {
+			log.Ctx(ctx).Err(err).Msg("pair: execute template")
+		}
+	}
+}
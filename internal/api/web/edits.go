@@ -0,0 +1,71 @@
+package web
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+	"net/http"
+	"slices"
+	"stash-vr/internal/edits"
+	"time"
+)
+
+type editData struct {
+	Id        string
+	SceneId   string
+	Source    string
+	Summary   string
+	Status    string
+	Attempts  int
+	Error     string
+	UpdatedAt string
+	CanRetry  bool
+}
+
+// editsData returns edits newest first and the number of edits not yet applied.
+func editsData(editQueue *edits.Queue) ([]editData, int) {
+	queued := editQueue.Edits()
+	slices.Reverse(queued)
+	out := make([]editData, len(queued))
+	unapplied := 0
+	for i, e := range queued {
+		out[i] = editData{
+			Id:        e.Id,
+			SceneId:   e.SceneId,
+			Source:    e.Source,
+			Summary:   e.Summary,
+			Status:    string(e.Status),
+			Attempts:  e.Attempts,
+			Error:     e.Error,
+			UpdatedAt: e.UpdatedAt.Local().Format(time.DateTime),
+			CanRetry:  e.Status != edits.StatusApplied,
+		}
+		if e.Status != edits.StatusApplied {
+			unapplied++
+		}
+	}
+	return out, unapplied
+}
+
+func EditRetryHandler(editQueue *edits.Queue) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "editId")
+		if !editQueue.Retry(r.Context(), id) {
+			http.NotFound(w, r)
+			return
+		}
+		log.Ctx(r.Context()).Info().Str("editId", id).Msg("Edit retried")
+		http.Redirect(w, r, "/#edits", http.StatusSeeOther)
+	}
+}
+
+func EditDiscardHandler(editQueue *edits.Queue) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "editId")
+		if !editQueue.Discard(r.Context(), id) {
+			http.NotFound(w, r)
+			return
+		}
+		log.Ctx(r.Context()).Info().Str("editId", id).Msg("Edit discarded")
+		http.Redirect(w, r, "/#edits", http.StatusSeeOther)
+	}
+}
@@ -0,0 +1,119 @@
+package web
+
+import (
+	"html/template"
+	"net/http"
+	"stash-vr/internal/api/auth"
+	"stash-vr/internal/api/internal"
+	"stash-vr/internal/share"
+	"stash-vr/internal/static"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+var shareTmpl = template.Must(template.ParseFS(static.Fs, "share.gohtml"))
+
+const (
+	defaultShareHours = 4
+	maxShareHours     = 7 * 24
+)
+
+type shareLinkData struct {
+	Id       string
+	Name     string
+	Sections string
+	Expires  string
+}
+
+type shareData struct {
+	Name          string
+	Sections      []string
+	Expires       string
+	HeresphereUrl string
+	DeovrUrl      string
+}
+
+func shareLinksData(signer *share.Signer) []shareLinkData {
+	grants := signer.Issued()
+	out := make([]shareLinkData, len(grants))
+	for i, g := range grants {
+		out[i] = shareLinkData{Id: g.Id, Name: g.Name, Sections: strings.Join(g.Sections, ", "), Expires: g.Expires.Local().Format(time.DateTime)}
+	}
+	return out
+}
+
+// ShareCreateHandler issues a share link to the posted sections and shows it once. Only the grant is kept so that the
+// link can be revoked, the link itself can't be shown again.
+func ShareCreateHandler(signer *share.Signer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "bad form", 400)
+			return
+		}
+
+		sections := r.PostForm["section"]
+		if len(sections) == 0 {
+			http.Error(w, "select at least one section", http.StatusBadRequest)
+			return
+		}
+		hours, err := strconv.Atoi(r.PostForm.Get("hours"))
+		if err != nil || hours <= 0 {
+			hours = defaultShareHours
+		}
+		hours = min(hours, maxShareHours)
+
+		g := share.Grant{
+			Name:     strings.TrimSpace(r.PostForm.Get("name")),
+			Sections: sections,
+			Expires:  time.Now().Add(time.Duration(hours) * time.Hour).Truncate(time.Second),
+		}
+		token, err := signer.Issue(ctx, g)
+		if err != nil {
+			log.Ctx(ctx).Err(err).Msg("Failed to issue share link")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		log.Ctx(ctx).Info().Str("name", g.Name).Strs("sections", g.Sections).Time("expires", g.Expires).Str("shareId", g.Id).Msg("Share link issued")
+
+		shareUrl := internal.GetBaseUrl(r) + auth.SharePath(token)
+		data := shareData{
+			Name:          g.Name,
+			Sections:      g.Sections,
+			Expires:       g.Expires.Local().Format(time.DateTime),
+			HeresphereUrl: shareUrl + "/heresphere",
+			DeovrUrl:      shareUrl + "/deovr",
+		}
+		if err := shareTmpl.Execute(w, data); err != nil {
+			log.Ctx(ctx).Err(err).Msg("share: execute template")
+		}
+	}
+}
+
+func ShareRevokeHandler(signer *share.Signer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "shareId")
+		if !signer.Revoke(r.Context(), id) {
+			http.NotFound(w, r)
+			return
+		}
+		log.Ctx(r.Context()).Info().Str("shareId", id).Msg("Share link revoked")
+		http.Redirect(w, r, "/#shares", http.StatusSeeOther)
+	}
+}
+
+func ShareRevokeAllHandler(signer *share.Signer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := signer.Rotate(r.Context()); err != nil {
+			log.Ctx(r.Context()).Err(err).Msg("Failed to rotate share key")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		log.Ctx(r.Context()).Info().Msg("All share links revoked")
+		http.Redirect(w, r, "/#shares", http.StatusSeeOther)
+	}
+}
@@ -0,0 +1,213 @@
+package web
+
+import (
+	"cmp"
+	"encoding/csv"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"html/template"
+	"net/http"
+	"slices"
+	"stash-vr/internal/api/internal"
+	"stash-vr/internal/config"
+	"stash-vr/internal/history"
+	"stash-vr/internal/static"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var statsTmpl = template.Must(template.New("stats.gohtml").Funcs(template.FuncMap{
+	"duration": func(d time.Duration) string { return d.Round(time.Second).String() },
+}).ParseFS(static.Fs, "stats.gohtml"))
+
+const (
+	topCount = 10
+	// completedFraction is how much of a scene must be covered for a viewing to count as completed.
+	completedFraction = 0.9
+)
+
+type dayStat struct {
+	Day      string
+	Watched  time.Duration
+	Viewings int
+}
+
+type rankedStat struct {
+	Name     string
+	Watched  time.Duration
+	Viewings int
+}
+
+type sceneStat struct {
+	rankedStat
+	Id             string
+	BestCompletion float64
+}
+
+type statsData struct {
+	Viewings       int
+	Watched        time.Duration
+	Completed      int
+	AvgCompletion  float64
+	Days           []dayStat
+	TopScenes      []sceneStat
+	TopPerformers  []rankedStat
+	TopTags        []rankedStat
+	TopStudios     []rankedStat
+	IsPersisted    bool
+	CompletedLimit float64
+}
+
+func buildStats(records []history.Record) statsData {
+	data := statsData{Viewings: len(records), CompletedLimit: completedFraction * 100}
+
+	days := map[string]*dayStat{}
+	scenes := map[string]*sceneStat{}
+	performers := map[string]*rankedStat{}
+	tags := map[string]*rankedStat{}
+	studios := map[string]*rankedStat{}
+
+	rank := func(m map[string]*rankedStat, name string, watched time.Duration) {
+		s, ok := m[name]
+		if !ok {
+			s = &rankedStat{Name: name}
+			m[name] = s
+		}
+		s.Watched += watched
+		s.Viewings++
+	}
+
+	totalCompletion := 0.0
+	for _, r := range records {
+		watched := r.WatchTime()
+		completion := r.Completion()
+		data.Watched += watched
+		totalCompletion += completion
+		if completion >= completedFraction {
+			data.Completed++
+		}
+
+		day := r.Start.Local().Format(time.DateOnly)
+		d, ok := days[day]
+		if !ok {
+			d = &dayStat{Day: day}
+			days[day] = d
+		}
+		d.Watched += watched
+		d.Viewings++
+
+		sc, ok := scenes[r.SceneId]
+		if !ok {
+			sc = &sceneStat{Id: r.SceneId}
+			scenes[r.SceneId] = sc
+		}
+		sc.Name = r.Title
+		sc.Watched += watched
+		sc.Viewings++
+		sc.BestCompletion = max(sc.BestCompletion, completion)
+
+		for _, p := range r.Performers {
+			rank(performers, p, watched)
+		}
+		for _, t := range r.Tags {
+			rank(tags, t, watched)
+		}
+		if r.Studio != "" {
+			rank(studios, r.Studio, watched)
+		}
+	}
+	if len(records) > 0 {
+		data.AvgCompletion = totalCompletion / float64(len(records)) * 100
+	}
+
+	for _, d := range days {
+		data.Days = append(data.Days, *d)
+	}
+	slices.SortFunc(data.Days, func(a, b dayStat) int {
+		return strings.Compare(b.Day, a.Day)
+	})
+
+	for _, s := range scenes {
+		s.BestCompletion *= 100
+		data.TopScenes = append(data.TopScenes, *s)
+	}
+	slices.SortFunc(data.TopScenes, func(a, b sceneStat) int {
+		return cmp.Compare(b.Watched, a.Watched)
+	})
+	data.TopScenes = data.TopScenes[:min(topCount, len(data.TopScenes))]
+
+	data.TopPerformers = topRanked(performers)
+	data.TopTags = topRanked(tags)
+	data.TopStudios = topRanked(studios)
+
+	return data
+}
+
+func topRanked(m map[string]*rankedStat) []rankedStat {
+	out := make([]rankedStat, 0, len(m))
+	for _, s := range m {
+		out = append(out, *s)
+	}
+	slices.SortFunc(out, func(a, b rankedStat) int {
+		if c := cmp.Compare(b.Watched, a.Watched); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return out[:min(topCount, len(out))]
+}
+
+func StatsHandler(historyStore *history.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data := buildStats(historyStore.Records())
+		data.IsPersisted = config.Application().ConfigPath != ""
+		if err := statsTmpl.Execute(w, data); err != nil {
+			log.Ctx(r.Context()).Err(err).Msg("stats: execute template")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+func HistoryJsonHandler(historyStore *history.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Disposition", `attachment; filename="history.json"`)
+		if err := internal.WriteJson(r.Context(), w, historyStore.Records()); err != nil {
+			log.Ctx(r.Context()).Err(err).Msg("history: write json")
+		}
+	}
+}
+
+func HistoryCsvHandler(historyStore *history.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="history.csv"`)
+
+		cw := csv.NewWriter(w)
+		_ = cw.Write([]string{"scene_id", "title", "studio", "performers", "tags", "device", "start", "end", "duration_seconds", "watched_seconds", "completion", "segments"})
+		for _, rec := range historyStore.Records() {
+			segments := make([]string, len(rec.Segments))
+			for i, s := range rec.Segments {
+				segments[i] = fmt.Sprintf("%.1f-%.1f@%gx", s.From, s.To, s.Speed)
+			}
+			_ = cw.Write([]string{
+				rec.SceneId,
+				rec.Title,
+				rec.Studio,
+				strings.Join(rec.Performers, "|"),
+				strings.Join(rec.Tags, "|"),
+				rec.Device,
+				rec.Start.Format(time.RFC3339),
+				rec.End.Format(time.RFC3339),
+				strconv.FormatFloat(rec.Duration, 'f', 1, 64),
+				strconv.FormatFloat(rec.WatchTime().Seconds(), 'f', 1, 64),
+				strconv.FormatFloat(rec.Completion(), 'f', 3, 64),
+				strings.Join(segments, " "),
+			})
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			log.Ctx(r.Context()).Err(err).Msg("history: write csv")
+		}
+	}
+}
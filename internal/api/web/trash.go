@@ -0,0 +1,70 @@
+package web
+
+import (
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+	"net/http"
+	"stash-vr/internal/audit"
+	"stash-vr/internal/library"
+	"time"
+)
+
+type trashedSceneData struct {
+	Id        string
+	Title     string
+	TrashedAt string
+	PurgeAt   string
+}
+
+func trashData(r *http.Request, libraryService *library.Service) []trashedSceneData {
+	trashed := libraryService.Trashed(r.Context())
+	out := make([]trashedSceneData, len(trashed))
+	for i, s := range trashed {
+		out[i] = trashedSceneData{
+			Id:        s.Id,
+			Title:     s.Title,
+			TrashedAt: s.TrashedAt.Local().Format(time.DateTime),
+			PurgeAt:   s.PurgeAt.Local().Format(time.DateTime),
+		}
+	}
+	return out
+}
+
+func TrashRestoreHandler(libraryService *library.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := audit.WithSource(r.Context(), webSource)
+		id := chi.URLParam(r, "sceneId")
+		if err := libraryService.Restore(ctx, id); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("videoId", id).Msg("Failed to restore scene")
+			writeTrashError(w, err)
+			return
+		}
+		http.Redirect(w, r, "/#trash", http.StatusSeeOther)
+	}
+}
+
+func TrashPurgeHandler(libraryService *library.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := audit.WithSource(r.Context(), webSource)
+		id := chi.URLParam(r, "sceneId")
+		if err := libraryService.PurgeTrashed(ctx, id); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("videoId", id).Msg("Failed to delete scene")
+			writeTrashError(w, err)
+			return
+		}
+		log.Ctx(ctx).Info().Str("videoId", id).Msg("Deleted scene from trash")
+		http.Redirect(w, r, "/#trash", http.StatusSeeOther)
+	}
+}
+
+func writeTrashError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, library.ErrWriteDenied):
+		status = http.StatusForbidden
+	case errors.Is(err, library.ErrNotTrashed):
+		status = http.StatusConflict
+	}
+	http.Error(w, err.Error(), status)
+}
@@ -9,8 +9,12 @@ import (
 	"html/template"
 	"net/http"
 	"stash-vr/internal/build"
+	"stash-vr/internal/certs"
 	"stash-vr/internal/config"
+	"stash-vr/internal/device"
+	"stash-vr/internal/edits"
 	"stash-vr/internal/library"
+	"stash-vr/internal/share"
 	"stash-vr/internal/stash"
 	"stash-vr/internal/stash/gql"
 	"stash-vr/internal/static"
@@ -23,6 +27,7 @@ const (
 	statusOk           = "OK"
 	statusError        = "ERROR"
 	statusUnauthorized = "UNAUTHORIZED"
+	statusUnavailable  = "UNAVAILABLE"
 )
 
 type filterData struct {
@@ -42,6 +47,7 @@ type stashData struct {
 	FilterData          []filterData
 	FilterOverrides     []filterOverride
 	SampleSceneCoverUrl string
+	Limitations         []string
 }
 
 type indexData struct {
@@ -49,14 +55,29 @@ type indexData struct {
 	Version                 string
 	LogLevel                string
 	ForceHTTPS              bool
+	IsTLS                   bool
+	HasCA                   bool
 	IsSyncMarkersAllowed    bool
+	IsReadOnly              bool
+	DeniedWrites            []string
 	StashGraphQLUrl         string
 	IsApiKeyProvided        bool
+	StashUsername           string
 	StashConnectionResponse string
 	StashData               *stashData
 	SectionCount            int
+	SectionNames            []string
 	LinkCount               int
 	SceneCount              int
+	Devices                 []deviceData
+	PendingPairings         []pendingPairingData
+	ShareLinks              []shareLinkData
+	Edits                   []editData
+	UnappliedEditCount      int
+	IsTrashEnabled          bool
+	TrashTag                string
+	TrashRetention          string
+	Trash                   []trashedSceneData
 }
 
 func sampleSceneCoverUrl(ctx context.Context, stashClient graphql.Client) (string, error) {
@@ -110,7 +131,7 @@ func filterOverrideRows(ctx context.Context, stashFilters []filterData) []filter
 	return rows
 }
 
-func IndexHandler(libraryService *library.Service) http.HandlerFunc {
+func IndexHandler(libraryService *library.Service, deviceStore *device.Store, shareSigner *share.Signer, editQueue *edits.Queue) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var redactFunc func(string) string
 		if !config.Application().IsRedactDisabled {
@@ -121,10 +142,25 @@ func IndexHandler(libraryService *library.Service) http.HandlerFunc {
 			Version:                 build.FullVersion(),
 			LogLevel:                config.Application().LogLevel,
 			ForceHTTPS:              config.Application().ForceHTTPS,
+			IsTLS:                   config.Application().IsTLS(),
+			IsReadOnly:              config.Application().ReadOnly,
+			DeniedWrites:            config.Application().DeniedWrites(),
 			StashGraphQLUrl:         config.Application().StashGraphQLUrl,
 			IsApiKeyProvided:        config.Application().StashApiKey != "",
+			StashUsername:           config.Application().StashUsername,
 			StashConnectionResponse: statusError,
+			Devices:                 devicesData(deviceStore),
+			PendingPairings:         pendingPairingsData(r, deviceStore),
+			ShareLinks:              shareLinksData(shareSigner),
 		}
+		data.Edits, data.UnappliedEditCount = editsData(editQueue)
+		if config.Application().TrashTag != "" {
+			data.IsTrashEnabled = true
+			data.TrashTag = config.Application().TrashTag
+			data.TrashRetention = config.Application().TrashRetention.String()
+			data.Trash = trashData(r, libraryService)
+		}
+		_, data.HasCA = certs.CA()
 
 		wg := sync.WaitGroup{}
 
@@ -133,7 +169,9 @@ func IndexHandler(libraryService *library.Service) http.HandlerFunc {
 			defer wg.Done()
 			if version, err := stash.GetVersion(r.Context(), libraryService.StashClient); err != nil {
 				var gqlErr *graphql.HTTPError
-				if errors.As(err, &gqlErr) {
+				if errors.Is(err, stash.ErrCircuitOpen) {
+					data.StashConnectionResponse = statusUnavailable
+				} else if errors.As(err, &gqlErr) {
 					if gqlErr.StatusCode == 401 {
 						data.StashConnectionResponse = statusUnauthorized
 					}
@@ -141,7 +179,7 @@ func IndexHandler(libraryService *library.Service) http.HandlerFunc {
 				log.Ctx(r.Context()).Warn().Err(err).Msg("Failed to retrieve stash version")
 			} else {
 				data.StashConnectionResponse = statusOk
-				data.StashData = &stashData{Version: version}
+				data.StashData = &stashData{Version: version, Limitations: libraryService.Capabilities(r.Context()).Limitations()}
 				data.StashData.FilterData, err = stashFilters(r.Context(), libraryService.StashClient)
 				if err != nil {
 					log.Ctx(r.Context()).Warn().Err(err).Msg("Failed to retrieve stash filters")
@@ -162,6 +200,9 @@ func IndexHandler(libraryService *library.Service) http.HandlerFunc {
 				log.Ctx(r.Context()).Warn().Err(err).Msg("Failed to retrieve sections")
 			} else {
 				data.SectionCount = len(sections)
+				for _, s := range sections {
+					data.SectionNames = append(data.SectionNames, s.Name)
+				}
 				data.LinkCount = libraryService.Stats.Links
 				data.SceneCount = libraryService.Stats.Scenes
 			}
@@ -215,3 +256,19 @@ func FiltersUpdateHandler() http.HandlerFunc {
 		http.Redirect(w, r, "/", http.StatusSeeOther)
 	}
 }
+
+// CAHandler serves the self-signed CA certificate for installing on headsets.
+func CAHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ca, ok := certs.CA()
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-x509-ca-cert")
+		w.Header().Set("Content-Disposition", `attachment; filename="stash-vr-ca.crt"`)
+		if _, err := w.Write(ca); err != nil {
+			log.Ctx(r.Context()).Err(err).Msg("ca: write")
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package audit
+
+import (
+	"context"
+	"stash-vr/internal/device"
+)
+
+const defaultSource = "Stash-VR"
+
+type ctxKeySource struct{}
+
+type ctxKeyReverts struct{}
+
+// WithSource sets who changes made with ctx are attributed to.
+func WithSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, ctxKeySource{}, source)
+}
+
+// Source returns who changes made with ctx are attributed to, the paired device making the request if not set.
+func Source(ctx context.Context) string {
+	if s, ok := ctx.Value(ctxKeySource{}).(string); ok && s != "" {
+		return s
+	}
+	if d, ok := device.FromContext(ctx); ok {
+		return d.Name
+	}
+	return defaultSource
+}
+
+// WithReverts marks changes made with ctx as reverting the entry with id.
+func WithReverts(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKeyReverts{}, id)
+}
+
+func reverts(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKeyReverts{}).(string)
+	return id
+}
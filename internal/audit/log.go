@@ -0,0 +1,290 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"stash-vr/internal/config"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	auditFile = "audit.jsonl"
+	// sessionGap is how long a source has to be idle for its next change to start a new session.
+	sessionGap = 30 * time.Minute
+	// maxEntries is how many of the latest entries are kept, older ones are dropped from the file once it has grown by
+	// a tenth more.
+	maxEntries = 10000
+)
+
+type Kind string
+
+const (
+	KindRating        Kind = "rating"
+	KindOrganized     Kind = "organized"
+	KindTags          Kind = "tags"
+	KindPerformers    Kind = "performers"
+	KindStudio        Kind = "studio"
+	KindGroups        Kind = "groups"
+	KindTitle         Kind = "title"
+	KindDate          Kind = "date"
+	KindCode          Kind = "code"
+	KindDetails       Kind = "details"
+	KindMarkerCreate  Kind = "marker_create"
+	KindMarkerUpdate  Kind = "marker_update"
+	KindMarkerDestroy Kind = "marker_destroy"
+	KindOCount        Kind = "o_counter"
+	KindPlayCount     Kind = "play_count"
+	KindDelete        Kind = "delete"
+)
+
+// Entry is a change made to a scene in Stash. Before and After are the values of the changed field, or the whole
+// marker, as JSON.
+type Entry struct {
+	Id      string          `json:"id"`
+	Time    time.Time       `json:"time"`
+	SceneId string          `json:"sceneId"`
+	Source  string          `json:"source"`
+	Session string          `json:"session"`
+	Kind    Kind            `json:"kind"`
+	Before  json.RawMessage `json:"before,omitempty"`
+	After   json.RawMessage `json:"after,omitempty"`
+	// Reverts is the id of the entry this change reverted.
+	Reverts string `json:"reverts,omitempty"`
+}
+
+// IsRevertible reports whether the change can be undone, deleted scenes can't be restored.
+func (e Entry) IsRevertible() bool {
+	return e.Kind != KindDelete
+}
+
+type session struct {
+	id   string
+	last time.Time
+}
+
+// Log is the audit log of the latest changes made to Stash. Entries are appended to a JSON Lines file in CONFIG_PATH,
+// or kept in memory only if CONFIG_PATH is not specified.
+type Log struct {
+	mu         sync.RWMutex
+	entries    []Entry
+	byId       map[string]Entry
+	sessions   map[string]session
+	revertedBy map[string]string
+}
+
+func Open(ctx context.Context) *Log {
+	l := &Log{byId: make(map[string]Entry), sessions: make(map[string]session), revertedBy: make(map[string]string)}
+	if config.Application().ConfigPath == "" {
+		log.Ctx(ctx).Info().Msg("CONFIG_PATH not specified, audit log will not persist")
+		return l
+	}
+
+	path := resolvePath()
+	f, err := os.Open(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to open audit log")
+		}
+		return l
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("skipping malformed audit log entry")
+			continue
+		}
+		l.add(e)
+	}
+	if err := scanner.Err(); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to read audit log")
+	}
+	if len(l.entries) > maxEntries {
+		l.compact(ctx)
+	}
+
+	log.Ctx(ctx).Debug().Int("entries", len(l.entries)).Msg("Loaded audit log")
+	return l
+}
+
+// Record appends a change to the log, attributed to the source of ctx.
+func (l *Log) Record(ctx context.Context, sceneId string, kind Kind, before any, after any) {
+	e := Entry{
+		Time:    time.Now(),
+		SceneId: sceneId,
+		Source:  Source(ctx),
+		Kind:    kind,
+		Reverts: reverts(ctx),
+	}
+	var err error
+	if e.Id, err = randomId(); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to record change")
+		return
+	}
+	if e.Before, err = marshal(before); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to record change")
+		return
+	}
+	if e.After, err = marshal(after); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to record change")
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s, ok := l.sessions[e.Source]
+	if !ok || e.Time.Sub(s.last) > sessionGap {
+		if s.id, err = randomId(); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("failed to record change")
+			return
+		}
+	}
+	e.Session = s.id
+	l.add(e)
+
+	if len(l.entries) > maxEntries+maxEntries/10 {
+		l.compact(ctx)
+		return
+	}
+	if config.Application().ConfigPath == "" {
+		return
+	}
+	if err := appendLine(resolvePath(), e); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to save audit log entry")
+	}
+}
+
+func (l *Log) add(e Entry) {
+	l.entries = append(l.entries, e)
+	l.byId[e.Id] = e
+	l.sessions[e.Source] = session{id: e.Session, last: e.Time}
+	if e.Reverts != "" {
+		l.revertedBy[e.Reverts] = e.Id
+	}
+}
+
+// compact drops all but the latest maxEntries entries and rewrites the file with the ones kept.
+func (l *Log) compact(ctx context.Context) {
+	drop := len(l.entries) - maxEntries
+	for _, e := range l.entries[:drop] {
+		delete(l.byId, e.Id)
+		delete(l.revertedBy, e.Id)
+	}
+	l.entries = slices.Clone(l.entries[drop:])
+	log.Ctx(ctx).Debug().Int("dropped", drop).Msg("Compacted audit log")
+
+	if config.Application().ConfigPath == "" {
+		return
+	}
+	if err := writeLines(resolvePath(), l.entries); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to compact audit log")
+	}
+}
+
+// Entries returns all entries, oldest first.
+func (l *Log) Entries() []Entry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return slices.Clone(l.entries)
+}
+
+func (l *Log) Find(id string) (Entry, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	e, ok := l.byId[id]
+	return e, ok
+}
+
+// Session returns the entries of a session, oldest first.
+func (l *Log) Session(id string) []Entry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	var out []Entry
+	for _, e := range l.entries {
+		if e.Session == id {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// RevertedBy returns the id of the entry that reverted the entry with id.
+func (l *Log) RevertedBy(id string) (string, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	by, ok := l.revertedBy[id]
+	return by, ok
+}
+
+func marshal(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+func randomId() (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func appendLine(path string, e Entry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// writeLines replaces the file at path with entries, through a temporary file so that a failed write leaves it intact.
+func writeLines(path string, entries []Entry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), auditFile+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := errors.Join(w.Flush(), f.Chmod(0o600), f.Close()); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func resolvePath() string {
+	return filepath.Join(config.Application().ConfigPath, auditFile)
+}
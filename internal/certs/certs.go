@@ -0,0 +1,276 @@
+package certs
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"stash-vr/internal/config"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	dir            = "tls"
+	caCertFile     = "ca.crt"
+	caKeyFile      = "ca.key"
+	serverCertFile = "server.crt"
+	serverKeyFile  = "server.key"
+
+	caValidity     = 10 * 365 * 24 * time.Hour
+	serverValidity = 397 * 24 * time.Hour
+	// renewBefore is how long before expiry the server certificate is replaced.
+	renewBefore = 30 * 24 * time.Hour
+)
+
+var (
+	caMu  sync.RWMutex
+	caPem []byte
+)
+
+// TLSConfig returns the configuration to serve HTTPS with, using TLS_CERT_FILE/TLS_KEY_FILE if set or else a
+// certificate signed by a self-signed CA. The CA and certificate are saved to CONFIG_PATH, or created on every start if
+// CONFIG_PATH is not specified.
+func TLSConfig(ctx context.Context) (*tls.Config, error) {
+	var cert tls.Certificate
+	var err error
+	if certFile := config.Application().TLSCertFile; certFile != "" {
+		cert, err = tls.LoadX509KeyPair(certFile, config.Application().TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load certificate: %w", err)
+		}
+	} else {
+		cert, err = selfSigned(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("self-signed certificate: %w", err)
+		}
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// CA returns the PEM encoded self-signed CA certificate, to be installed on headsets.
+func CA() ([]byte, bool) {
+	if !config.Application().TLSSelfSigned {
+		return nil, false
+	}
+	caMu.RLock()
+	defer caMu.RUnlock()
+	return caPem, caPem != nil
+}
+
+func selfSigned(ctx context.Context) (tls.Certificate, error) {
+	ca, caKey, err := loadOrCreateCA(ctx)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	hosts := serverHosts()
+	certPem, keyPem, err := readPair(serverCertFile, serverKeyFile)
+	if err == nil {
+		if cert, err := tls.X509KeyPair(certPem, keyPem); err == nil && isCurrent(cert, ca, hosts) {
+			return cert, nil
+		}
+		log.Ctx(ctx).Info().Msg("Server certificate expiring or hosts changed, issuing a new one")
+	}
+
+	certPem, keyPem, err = issue(ca, caKey, hosts)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	writePair(ctx, serverCertFile, certPem, serverKeyFile, keyPem)
+	log.Ctx(ctx).Info().Strs("hosts", hosts).Msg("Issued server certificate")
+	return tls.X509KeyPair(certPem, keyPem)
+}
+
+func loadOrCreateCA(ctx context.Context) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	certPem, keyPem, err := readPair(caCertFile, caKeyFile)
+	if err == nil {
+		cert, key, err := parsePair(certPem, keyPem)
+		if err == nil && time.Now().Before(cert.NotAfter) {
+			setCA(certPem)
+			return cert, key, nil
+		}
+		log.Ctx(ctx).Warn().Err(err).Msg("Stored CA unusable, creating a new one")
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber(),
+		Subject:               pkix.Name{CommonName: "Stash-VR CA", Organization: []string{"Stash-VR"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	certPem = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	writePair(ctx, caCertFile, certPem, caKeyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+	setCA(certPem)
+	log.Ctx(ctx).Info().Msg("Created self-signed CA, install ca.crt on your headset to trust Stash-VR")
+	return cert, key, nil
+}
+
+func issue(ca *x509.Certificate, caKey *ecdsa.PrivateKey, hosts []string) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serialNumber(),
+		Subject:      pkix.Name{CommonName: hosts[0], Organization: []string{"Stash-VR"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(serverValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, h)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), nil
+}
+
+// isCurrent reports whether cert is signed by ca, isn't about to expire and covers all hosts.
+func isCurrent(cert tls.Certificate, ca *x509.Certificate, hosts []string) bool {
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return false
+	}
+	if leaf.CheckSignatureFrom(ca) != nil || time.Now().Add(renewBefore).After(leaf.NotAfter) {
+		return false
+	}
+	for _, h := range hosts {
+		if leaf.VerifyHostname(h) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// serverHosts returns the names and addresses the server certificate is valid for: TLS_HOSTS, localhost, the
+// hostname and the addresses of all network interfaces.
+func serverHosts() []string {
+	hosts := slices.Clone(config.Application().TLSHosts)
+	hosts = append(hosts, "localhost")
+	if name, err := os.Hostname(); err == nil {
+		hosts = append(hosts, name)
+	}
+	if addrs, err := net.InterfaceAddrs(); err == nil {
+		for _, a := range addrs {
+			if ipNet, ok := a.(*net.IPNet); ok && !ipNet.IP.IsLinkLocalUnicast() {
+				hosts = append(hosts, ipNet.IP.String())
+			}
+		}
+	}
+	slices.Sort(hosts[len(config.Application().TLSHosts):])
+	return slices.Compact(hosts)
+}
+
+func parsePair(certPem []byte, keyPem []byte) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	certBlock, _ := pem.Decode(certPem)
+	keyBlock, _ := pem.Decode(keyPem)
+	if certBlock == nil || keyBlock == nil {
+		return nil, nil, errors.New("invalid PEM")
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := x509.ParseECPrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+func readPair(certFile string, keyFile string) ([]byte, []byte, error) {
+	if config.Application().ConfigPath == "" {
+		return nil, nil, os.ErrNotExist
+	}
+	certPem, err := os.ReadFile(resolvePath(certFile))
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPem, err := os.ReadFile(resolvePath(keyFile))
+	if err != nil {
+		return nil, nil, err
+	}
+	return certPem, keyPem, nil
+}
+
+func writePair(ctx context.Context, certFile string, certPem []byte, keyFile string, keyPem []byte) {
+	if config.Application().ConfigPath == "" {
+		log.Ctx(ctx).Warn().Msg("CONFIG_PATH not specified, self-signed certificates will change on every restart")
+		return
+	}
+	if err := os.MkdirAll(resolvePath(""), 0o700); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("error creating tls directory")
+		return
+	}
+	if err := os.WriteFile(resolvePath(certFile), certPem, 0o644); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("file", certFile).Msg("failed to save certificate")
+	}
+	if err := os.WriteFile(resolvePath(keyFile), keyPem, 0o600); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("file", keyFile).Msg("failed to save key")
+	}
+}
+
+func serialNumber() *big.Int {
+	n, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	return n
+}
+
+func resolvePath(file string) string {
+	return filepath.Join(config.Application().ConfigPath, dir, file)
+}
+
+func setCA(certPem []byte) {
+	caMu.Lock()
+	defer caMu.Unlock()
+	caPem = bytes.Clone(certPem)
+}
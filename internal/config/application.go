@@ -4,7 +4,9 @@ import (
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"os"
+	"slices"
 	"strings"
+	"time"
 )
 
 const (
@@ -12,6 +14,8 @@ const (
 	envKeyStashGraphQLUrl    = "STASH_GRAPHQL_URL"
 	envKeyStashApiKey        = "STASH_API_KEY"
 	envKeyFavoriteTag        = "FAVORITE_TAG"
+	envKeyWatchLaterTag      = "WATCH_LATER_TAG"
+	envKeyCreatePerformers   = "CREATE_PERFORMERS"
 	envKeyLogLevel           = "LOG_LEVEL"
 	envKeyDisableLogColor    = "DISABLE_LOG_COLOR"
 	envKeyDisableRedact      = "DISABLE_REDACT"
@@ -20,21 +24,125 @@ const (
 	envKeyExcludeSortName    = "EXCLUDE_SORT_NAME"
 	envKeyUserConfigPath     = "CONFIG_PATH"
 	envKeyGenerateSummaryIds = "GENERATE_SUMMARY_IDS"
+	envKeyReplayHeatmap      = "REPLAY_HEATMAP"
+	envKeyReplayMarkerTag    = "REPLAY_MARKER_TAG"
+	envKeyReplayMarkerCount  = "REPLAY_MARKER_COUNT"
+	envKeyHeresphereAccounts = "HERESPHERE_ACCOUNTS"
+	envKeyHeresphereTokenTTL = "HERESPHERE_TOKEN_TTL"
+	envKeyAuthBasic          = "AUTH_BASIC"
+	envKeyAuthTokens         = "AUTH_TOKENS"
+	envKeyAuthAllowIPs       = "AUTH_ALLOW_IPS"
+	envKeyAuthWeb            = "AUTH_WEB"
+	envKeyAuthHeresphere     = "AUTH_HERESPHERE"
+	envKeyAuthDeovr          = "AUTH_DEOVR"
+	envKeyAuthCover          = "AUTH_COVER"
+	envKeyMediaProxy         = "MEDIA_PROXY"
+	envKeyMediaUrlTTL        = "MEDIA_URL_TTL"
+	envKeyMediaStreamUrlTTL  = "MEDIA_STREAM_URL_TTL"
+	envKeyStashUsername      = "STASH_USERNAME"
+	envKeyStashPassword      = "STASH_PASSWORD"
+	envKeyStashCAFile        = "STASH_CA_FILE"
+	envKeyStashClientCert    = "STASH_CLIENT_CERT_FILE"
+	envKeyStashClientKey     = "STASH_CLIENT_KEY_FILE"
+	envKeyStashSkipVerify    = "STASH_INSECURE_SKIP_VERIFY"
+	envKeyStashConnTimeout   = "STASH_CONNECT_TIMEOUT"
+	envKeyStashReadTimeout   = "STASH_READ_TIMEOUT"
+	envKeyTLSCertFile        = "TLS_CERT_FILE"
+	envKeyTLSKeyFile         = "TLS_KEY_FILE"
+	envKeyTLSSelfSigned      = "TLS_SELF_SIGNED"
+	envKeyTLSHosts           = "TLS_HOSTS"
+	envKeyTLSRedirectAddress = "TLS_REDIRECT_ADDRESS"
+	envKeyReadOnly           = "READ_ONLY"
+	envKeyDenyWrite          = "DENY_WRITE"
+	envKeyTrashTag           = "TRASH_TAG"
+	envKeyTrashRetention     = "TRASH_RETENTION"
 )
 
+// Route groups that can be protected individually by authentication.
+const (
+	AuthGroupWeb        = "web"
+	AuthGroupHeresphere = "heresphere"
+	AuthGroupDeovr      = "deovr"
+	AuthGroupCover      = "cover"
+)
+
+// Kinds of changes to Stash that can be denied.
+const (
+	WriteRating     = "rating"
+	WriteFavorite   = "favorite"
+	WriteTags       = "tags"
+	WritePerformers = "performers"
+	WriteStudio     = "studio"
+	WriteGroups     = "groups"
+	WriteMetadata   = "metadata"
+	WriteMarkers    = "markers"
+	WriteOCount     = "o-count"
+	WritePlay       = "play"
+	WriteOrganized  = "organized"
+	WriteDelete     = "delete"
+)
+
+// Writes lists all kinds of changes to Stash.
+var Writes = []string{WriteRating, WriteFavorite, WriteTags, WritePerformers, WriteStudio, WriteGroups, WriteMetadata, WriteMarkers, WriteOCount, WritePlay, WriteOrganized, WriteDelete}
+
+type Account struct {
+	Username string
+	Password string
+}
+
 type ApplicationConfig struct {
-	ListenAddress      string
-	StashGraphQLUrl    string
-	StashApiKey        string
-	FavoriteTag        string
-	LogLevel           string
-	DisableLogColor    bool
-	IsRedactDisabled   bool
-	ForceHTTPS         bool
-	HeatmapHeightPx    int
-	ExcludeSortName    string
-	ConfigPath         string
-	GenerateSummaryIds bool
+	ListenAddress           string
+	StashGraphQLUrl         string
+	StashApiKey             string
+	StashUsername           string
+	StashPassword           string
+	StashCAFile             string
+	StashClientCertFile     string
+	StashClientKeyFile      string
+	StashInsecureSkipVerify bool
+	StashConnectTimeout     time.Duration
+	StashReadTimeout        time.Duration
+	FavoriteTag             string
+	WatchLaterTag           string
+	CreatePerformers        bool
+	LogLevel                string
+	DisableLogColor         bool
+	IsRedactDisabled        bool
+	ForceHTTPS              bool
+	HeatmapHeightPx         int
+	ExcludeSortName         string
+	ConfigPath              string
+	GenerateSummaryIds      bool
+	ReplayHeatmap           bool
+	ReplayMarkerTag         string
+	ReplayMarkerCount       int
+	HeresphereAccounts      []Account
+	// HeresphereTokenTTL is how long HereSphere logins stay valid.
+	HeresphereTokenTTL time.Duration
+	AuthBasic          []Account
+	AuthTokens         []string
+	AuthAllowIPs       []string
+	// AuthGroups maps a route group to the authentication methods accepted for it.
+	AuthGroups map[string][]string
+	MediaProxy bool
+	// MediaUrlTTL is how long signed media proxy urls stay valid.
+	MediaUrlTTL time.Duration
+	// MediaStreamUrlTTL is how long signed media proxy urls of videos stay valid, players keep requesting ranges of a
+	// video for as long as it's open.
+	MediaStreamUrlTTL  time.Duration
+	TLSCertFile        string
+	TLSKeyFile         string
+	TLSSelfSigned      bool
+	TLSHosts           []string
+	TLSRedirectAddress string
+	// ReadOnly denies all changes to Stash.
+	ReadOnly bool
+	// DenyWrite lists the kinds of changes to Stash that are denied.
+	DenyWrite []string
+	// TrashTag enables moving deleted scenes to the trash by tagging them with it.
+	TrashTag string
+	// TrashRetention is how long scenes stay in the trash before they are purged.
+	TrashRetention time.Duration
 }
 
 var applicationConfig ApplicationConfig
@@ -52,6 +160,12 @@ func Init() {
 	pflag.String(envKeyFavoriteTag, "FAVORITE", "Name of tag in Stash to hold scenes marked as favorites")
 	_ = viper.BindPFlag(envKeyFavoriteTag, pflag.Lookup(envKeyFavoriteTag))
 
+	pflag.String(envKeyWatchLaterTag, "WATCH_LATER", "Name of tag in Stash added by the /later command")
+	_ = viper.BindPFlag(envKeyWatchLaterTag, pflag.Lookup(envKeyWatchLaterTag))
+
+	pflag.Bool(envKeyCreatePerformers, false, "Create performers added in HereSphere that don't exist in Stash")
+	_ = viper.BindPFlag(envKeyCreatePerformers, pflag.Lookup(envKeyCreatePerformers))
+
 	pflag.String(envKeyLogLevel, "info", "Set log level - trace, debug, warn, info or error")
 	_ = viper.BindPFlag(envKeyLogLevel, pflag.Lookup(envKeyLogLevel))
 
@@ -76,6 +190,102 @@ func Init() {
 	pflag.String(envKeyGenerateSummaryIds, "", "Generate summary ids for categorized tags")
 	_ = viper.BindPFlag(envKeyGenerateSummaryIds, pflag.Lookup(envKeyGenerateSummaryIds))
 
+	pflag.Bool(envKeyReplayHeatmap, false, "Overlay covers of watched non-interactive scenes with a heatmap of the most replayed parts")
+	_ = viper.BindPFlag(envKeyReplayHeatmap, pflag.Lookup(envKeyReplayHeatmap))
+
+	pflag.String(envKeyReplayMarkerTag, "", "Create markers with this tag at the most replayed parts of watched scenes")
+	_ = viper.BindPFlag(envKeyReplayMarkerTag, pflag.Lookup(envKeyReplayMarkerTag))
+
+	pflag.Int(envKeyReplayMarkerCount, 3, "Max number of most replayed markers per scene")
+	_ = viper.BindPFlag(envKeyReplayMarkerCount, pflag.Lookup(envKeyReplayMarkerCount))
+
+	pflag.String(envKeyHeresphereAccounts, "", "Comma separated list of username:password required to log in from HereSphere")
+	_ = viper.BindPFlag(envKeyHeresphereAccounts, pflag.Lookup(envKeyHeresphereAccounts))
+
+	pflag.Duration(envKeyHeresphereTokenTTL, 30*24*time.Hour, "How long HereSphere logins stay valid")
+	_ = viper.BindPFlag(envKeyHeresphereTokenTTL, pflag.Lookup(envKeyHeresphereTokenTTL))
+
+	pflag.String(envKeyAuthBasic, "", "Comma separated list of username:password accepted by the basic auth method")
+	_ = viper.BindPFlag(envKeyAuthBasic, pflag.Lookup(envKeyAuthBasic))
+
+	pflag.String(envKeyAuthTokens, "", "Comma separated list of tokens accepted by the token auth method")
+	_ = viper.BindPFlag(envKeyAuthTokens, pflag.Lookup(envKeyAuthTokens))
+
+	pflag.String(envKeyAuthAllowIPs, "", "Comma separated list of IPs or CIDRs accepted by the ip auth method")
+	_ = viper.BindPFlag(envKeyAuthAllowIPs, pflag.Lookup(envKeyAuthAllowIPs))
+
+	pflag.String(envKeyAuthWeb, "", "Auth methods (basic, token, ip) accepted for the web index page")
+	_ = viper.BindPFlag(envKeyAuthWeb, pflag.Lookup(envKeyAuthWeb))
+
+	pflag.String(envKeyAuthHeresphere, "", "Auth methods (basic, token, ip) accepted for HereSphere endpoints")
+	_ = viper.BindPFlag(envKeyAuthHeresphere, pflag.Lookup(envKeyAuthHeresphere))
+
+	pflag.String(envKeyAuthDeovr, "", "Auth methods (basic, token, ip) accepted for DeoVR endpoints")
+	_ = viper.BindPFlag(envKeyAuthDeovr, pflag.Lookup(envKeyAuthDeovr))
+
+	pflag.String(envKeyAuthCover, "", "Auth methods (basic, token, ip) accepted for cover images")
+	_ = viper.BindPFlag(envKeyAuthCover, pflag.Lookup(envKeyAuthCover))
+
+	pflag.Bool(envKeyMediaProxy, false, "Serve scene media through Stash-VR instead of handing out Stash urls with the api key")
+	_ = viper.BindPFlag(envKeyMediaProxy, pflag.Lookup(envKeyMediaProxy))
+
+	pflag.Duration(envKeyMediaUrlTTL, time.Hour, "How long signed media proxy urls stay valid")
+	_ = viper.BindPFlag(envKeyMediaUrlTTL, pflag.Lookup(envKeyMediaUrlTTL))
+
+	pflag.Duration(envKeyMediaStreamUrlTTL, 24*time.Hour, "How long signed media proxy urls of videos stay valid")
+	_ = viper.BindPFlag(envKeyMediaStreamUrlTTL, pflag.Lookup(envKeyMediaStreamUrlTTL))
+
+	pflag.String(envKeyStashUsername, "", "Stash username, to log in with instead of an api key")
+	_ = viper.BindPFlag(envKeyStashUsername, pflag.Lookup(envKeyStashUsername))
+
+	pflag.String(envKeyStashPassword, "", "Stash password")
+	_ = viper.BindPFlag(envKeyStashPassword, pflag.Lookup(envKeyStashPassword))
+
+	pflag.String(envKeyStashCAFile, "", "CA certificates (PEM) to trust for Stash in addition to the system ones")
+	_ = viper.BindPFlag(envKeyStashCAFile, pflag.Lookup(envKeyStashCAFile))
+
+	pflag.String(envKeyStashClientCert, "", "Client certificate (PEM) to authenticate to Stash with")
+	_ = viper.BindPFlag(envKeyStashClientCert, pflag.Lookup(envKeyStashClientCert))
+
+	pflag.String(envKeyStashClientKey, "", "Key file of STASH_CLIENT_CERT_FILE")
+	_ = viper.BindPFlag(envKeyStashClientKey, pflag.Lookup(envKeyStashClientKey))
+
+	pflag.Bool(envKeyStashSkipVerify, false, "Don't verify the certificate of Stash")
+	_ = viper.BindPFlag(envKeyStashSkipVerify, pflag.Lookup(envKeyStashSkipVerify))
+
+	pflag.Duration(envKeyStashConnTimeout, 10*time.Second, "Timeout for connecting to Stash")
+	_ = viper.BindPFlag(envKeyStashConnTimeout, pflag.Lookup(envKeyStashConnTimeout))
+
+	pflag.Duration(envKeyStashReadTimeout, 2*time.Minute, "Timeout for Stash to start responding to a request")
+	_ = viper.BindPFlag(envKeyStashReadTimeout, pflag.Lookup(envKeyStashReadTimeout))
+
+	pflag.String(envKeyTLSCertFile, "", "Certificate file to serve HTTPS with")
+	_ = viper.BindPFlag(envKeyTLSCertFile, pflag.Lookup(envKeyTLSCertFile))
+
+	pflag.String(envKeyTLSKeyFile, "", "Key file of TLS_CERT_FILE")
+	_ = viper.BindPFlag(envKeyTLSKeyFile, pflag.Lookup(envKeyTLSKeyFile))
+
+	pflag.Bool(envKeyTLSSelfSigned, false, "Serve HTTPS with a certificate signed by a generated self-signed CA")
+	_ = viper.BindPFlag(envKeyTLSSelfSigned, pflag.Lookup(envKeyTLSSelfSigned))
+
+	pflag.String(envKeyTLSHosts, "", "Comma separated list of extra host names and IPs for the self-signed certificate")
+	_ = viper.BindPFlag(envKeyTLSHosts, pflag.Lookup(envKeyTLSHosts))
+
+	pflag.String(envKeyTLSRedirectAddress, "", "Local address to listen on for HTTP requests to redirect to HTTPS")
+	_ = viper.BindPFlag(envKeyTLSRedirectAddress, pflag.Lookup(envKeyTLSRedirectAddress))
+
+	pflag.Bool(envKeyReadOnly, false, "Never make any changes to Stash")
+	_ = viper.BindPFlag(envKeyReadOnly, pflag.Lookup(envKeyReadOnly))
+
+	pflag.String(envKeyDenyWrite, "", "Comma separated list of changes to Stash to deny (rating, favorite, tags, performers, markers, o-count, play, organized, delete)")
+	_ = viper.BindPFlag(envKeyDenyWrite, pflag.Lookup(envKeyDenyWrite))
+
+	pflag.String(envKeyTrashTag, "", "Move deleted scenes to the trash by tagging them with this tag instead of deleting them")
+	_ = viper.BindPFlag(envKeyTrashTag, pflag.Lookup(envKeyTrashTag))
+
+	pflag.Duration(envKeyTrashRetention, 7*24*time.Hour, "How long scenes stay in the trash before they are deleted")
+	_ = viper.BindPFlag(envKeyTrashRetention, pflag.Lookup(envKeyTrashRetention))
+
 	pflag.BoolP("help", "h", false, "Display usage information")
 	_ = viper.BindPFlag("help", pflag.Lookup("help"))
 
@@ -92,6 +302,8 @@ func Init() {
 	applicationConfig.StashGraphQLUrl = viper.GetString(envKeyStashGraphQLUrl)
 	applicationConfig.StashApiKey = viper.GetString(envKeyStashApiKey)
 	applicationConfig.FavoriteTag = viper.GetString(envKeyFavoriteTag)
+	applicationConfig.WatchLaterTag = viper.GetString(envKeyWatchLaterTag)
+	applicationConfig.CreatePerformers = viper.GetBool(envKeyCreatePerformers)
 	applicationConfig.LogLevel = strings.ToLower(viper.GetString(envKeyLogLevel))
 	applicationConfig.DisableLogColor = viper.GetBool(envKeyDisableLogColor)
 	applicationConfig.IsRedactDisabled = viper.GetBool(envKeyDisableRedact)
@@ -100,7 +312,84 @@ func Init() {
 	applicationConfig.ExcludeSortName = viper.GetString(envKeyExcludeSortName)
 	applicationConfig.ConfigPath = viper.GetString(envKeyUserConfigPath)
 	applicationConfig.GenerateSummaryIds = viper.GetBool(envKeyGenerateSummaryIds)
+	applicationConfig.ReplayHeatmap = viper.GetBool(envKeyReplayHeatmap)
+	applicationConfig.ReplayMarkerTag = viper.GetString(envKeyReplayMarkerTag)
+	applicationConfig.ReplayMarkerCount = viper.GetInt(envKeyReplayMarkerCount)
+	applicationConfig.HeresphereAccounts = parseAccounts(viper.GetString(envKeyHeresphereAccounts))
+	applicationConfig.HeresphereTokenTTL = viper.GetDuration(envKeyHeresphereTokenTTL)
+	applicationConfig.AuthBasic = parseAccounts(viper.GetString(envKeyAuthBasic))
+	applicationConfig.AuthTokens = parseList(viper.GetString(envKeyAuthTokens))
+	applicationConfig.AuthAllowIPs = parseList(viper.GetString(envKeyAuthAllowIPs))
+	applicationConfig.AuthGroups = map[string][]string{
+		AuthGroupWeb:        parseList(strings.ToLower(viper.GetString(envKeyAuthWeb))),
+		AuthGroupHeresphere: parseList(strings.ToLower(viper.GetString(envKeyAuthHeresphere))),
+		AuthGroupDeovr:      parseList(strings.ToLower(viper.GetString(envKeyAuthDeovr))),
+		AuthGroupCover:      parseList(strings.ToLower(viper.GetString(envKeyAuthCover))),
+	}
+	applicationConfig.MediaProxy = viper.GetBool(envKeyMediaProxy)
+	applicationConfig.MediaUrlTTL = viper.GetDuration(envKeyMediaUrlTTL)
+	applicationConfig.MediaStreamUrlTTL = viper.GetDuration(envKeyMediaStreamUrlTTL)
+	applicationConfig.StashUsername = viper.GetString(envKeyStashUsername)
+	applicationConfig.StashPassword = viper.GetString(envKeyStashPassword)
+	applicationConfig.StashCAFile = viper.GetString(envKeyStashCAFile)
+	applicationConfig.StashClientCertFile = viper.GetString(envKeyStashClientCert)
+	applicationConfig.StashClientKeyFile = viper.GetString(envKeyStashClientKey)
+	applicationConfig.StashInsecureSkipVerify = viper.GetBool(envKeyStashSkipVerify)
+	applicationConfig.StashConnectTimeout = viper.GetDuration(envKeyStashConnTimeout)
+	applicationConfig.StashReadTimeout = viper.GetDuration(envKeyStashReadTimeout)
+	applicationConfig.TLSCertFile = viper.GetString(envKeyTLSCertFile)
+	applicationConfig.TLSKeyFile = viper.GetString(envKeyTLSKeyFile)
+	applicationConfig.TLSSelfSigned = viper.GetBool(envKeyTLSSelfSigned)
+	applicationConfig.TLSHosts = parseList(viper.GetString(envKeyTLSHosts))
+	applicationConfig.TLSRedirectAddress = viper.GetString(envKeyTLSRedirectAddress)
+	applicationConfig.ReadOnly = viper.GetBool(envKeyReadOnly)
+	applicationConfig.DenyWrite = parseList(strings.ToLower(viper.GetString(envKeyDenyWrite)))
+	applicationConfig.TrashTag = viper.GetString(envKeyTrashTag)
+	applicationConfig.TrashRetention = viper.GetDuration(envKeyTrashRetention)
+
+}
+
+func parseAccounts(s string) []Account {
+	var accounts []Account
+	for _, entry := range strings.Split(s, ",") {
+		username, password, found := strings.Cut(strings.TrimSpace(entry), ":")
+		if !found || username == "" {
+			continue
+		}
+		accounts = append(accounts, Account{Username: username, Password: password})
+	}
+	return accounts
+}
+
+func parseList(s string) []string {
+	var out []string
+	for _, entry := range strings.Split(s, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
+
+// IsTLS reports whether Stash-VR serves HTTPS itself.
+func (a ApplicationConfig) IsTLS() bool {
+	return a.TLSCertFile != "" || a.TLSSelfSigned
+}
 
+// CanWrite reports whether changes of kind to Stash are allowed.
+func (a ApplicationConfig) CanWrite(kind string) bool {
+	return !a.ReadOnly && !slices.Contains(a.DenyWrite, kind)
+}
+
+// DeniedWrites returns the kinds of changes to Stash that are denied.
+func (a ApplicationConfig) DeniedWrites() []string {
+	var denied []string
+	for _, kind := range Writes {
+		if !a.CanWrite(kind) {
+			denied = append(denied, kind)
+		}
+	}
+	return denied
 }
 
 func Application() ApplicationConfig {
@@ -110,6 +399,10 @@ func Application() ApplicationConfig {
 func (a ApplicationConfig) Redacted() ApplicationConfig {
 	a.StashGraphQLUrl = Redacted(a.StashGraphQLUrl)
 	a.StashApiKey = Redacted(a.StashApiKey)
+	a.StashPassword = Redacted(a.StashPassword)
 	a.ConfigPath = Redacted(a.ConfigPath)
+	a.HeresphereAccounts = redactedAccounts(a.HeresphereAccounts)
+	a.AuthBasic = redactedAccounts(a.AuthBasic)
+	a.AuthTokens = redactedList(a.AuthTokens)
 	return a
 }
@@ -4,6 +4,22 @@ import (
 	"fmt"
 )
 
+func redactedAccounts(accounts []Account) []Account {
+	out := make([]Account, len(accounts))
+	for i, a := range accounts {
+		out[i] = Account{Username: a.Username, Password: Redacted(a.Password)}
+	}
+	return out
+}
+
+func redactedList(ss []string) []string {
+	out := make([]string, len(ss))
+	for i, s := range ss {
+		out[i] = Redacted(s)
+	}
+	return out
+}
+
 func Redacted(s string) string {
 	if Application().IsRedactDisabled {
 		return s
@@ -0,0 +1,15 @@
+package device
+
+import "context"
+
+type ctxKeyDevice struct{}
+
+func WithDevice(ctx context.Context, d Device) context.Context {
+	return context.WithValue(ctx, ctxKeyDevice{}, d)
+}
+
+// FromContext returns the paired device making the request, ok is false if the request isn't made with a device token.
+func FromContext(ctx context.Context) (Device, bool) {
+	d, ok := ctx.Value(ctxKeyDevice{}).(Device)
+	return d, ok
+}
@@ -0,0 +1,130 @@
+package device
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	codeLength   = 6
+	codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	codeLifetime = 10 * time.Minute
+	// maxFailedPairs is how many invalid codes a client can try within codeLifetime before it's locked out until then.
+	maxFailedPairs = 5
+	// maxFailedPairsTotal is how many invalid codes all clients can try within codeLifetime before pairing is refused
+	// until then, so that guessing from many addresses doesn't pay off either. Pending codes stay valid.
+	maxFailedPairsTotal = 20
+)
+
+var ErrInvalidCode = errors.New("invalid or expired pairing code")
+
+var ErrTooManyAttempts = errors.New("too many invalid pairing codes, try again later")
+
+type failures struct {
+	count int
+	since time.Time
+}
+
+type pairing struct {
+	name    string
+	expires time.Time
+}
+
+// Pending is a pairing code waiting to be used by a headset.
+type Pending struct {
+	Code    string
+	Name    string
+	Expires time.Time
+}
+
+// NewPairingCode creates a short-lived, single use code that a headset can exchange for a device token.
+func (s *Store) NewPairingCode(name string) (string, error) {
+	code := make([]byte, codeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(codeAlphabet))))
+		if err != nil {
+			return "", err
+		}
+		code[i] = codeAlphabet[n.Int64()]
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prunePending()
+	s.pending[string(code)] = pairing{name: name, expires: time.Now().Add(codeLifetime)}
+	return string(code), nil
+}
+
+// PendingCodes returns pairing codes not yet used or expired.
+func (s *Store) PendingCodes() []Pending {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prunePending()
+	out := make([]Pending, 0, len(s.pending))
+	for code, p := range s.pending {
+		out = append(out, Pending{Code: code, Name: p.name, Expires: p.expires})
+	}
+	return out
+}
+
+// Pair consumes a pairing code and registers a new device, returning the device and its token. Clients trying too
+// many invalid codes, or all clients once too many invalid codes were tried in total, get ErrTooManyAttempts.
+func (s *Store) Pair(ctx context.Context, code string, client string, fallbackName string) (Device, string, error) {
+	code = strings.ToUpper(code)
+
+	s.mu.Lock()
+	s.prunePending()
+	if s.failed[client].count >= maxFailedPairs || s.failed[""].count >= maxFailedPairsTotal {
+		s.mu.Unlock()
+		return Device{}, "", ErrTooManyAttempts
+	}
+	p, ok := s.pending[code]
+	delete(s.pending, code)
+	if !ok {
+		s.fail(client)
+		if s.fail("") == maxFailedPairsTotal {
+			log.Ctx(ctx).Warn().Msg("Too many invalid pairing codes, refusing pairing for a while")
+		}
+	}
+	s.mu.Unlock()
+	if !ok {
+		return Device{}, "", ErrInvalidCode
+	}
+
+	name := p.name
+	if name == "" {
+		name = fallbackName
+	}
+	return s.add(ctx, name)
+}
+
+// fail counts an invalid code tried by client and returns how many it tried within codeLifetime.
+func (s *Store) fail(client string) int {
+	f := s.failed[client]
+	if f.count == 0 {
+		f.since = time.Now()
+	}
+	f.count++
+	s.failed[client] = f
+	return f.count
+}
+
+func (s *Store) prunePending() {
+	now := time.Now()
+	for code, p := range s.pending {
+		if now.After(p.expires) {
+			delete(s.pending, code)
+		}
+	}
+	for client, f := range s.failed {
+		if now.Sub(f.since) > codeLifetime {
+			delete(s.failed, client)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package device
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"stash-vr/internal/config"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	devicesFile = "devices.json"
+)
+
+// Device is a paired headset. Only a hash of its token is kept, the token itself is handed out once when pairing.
+type Device struct {
+	Id        string    `json:"id"`
+	Name      string    `json:"name"`
+	TokenHash string    `json:"tokenHash"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Store holds paired devices and pending pairing codes. Devices are saved to CONFIG_PATH, or kept in memory only if
+// CONFIG_PATH is not specified.
+type Store struct {
+	mu      sync.RWMutex
+	devices []Device
+	pending map[string]pairing
+	// failed counts invalid pairing codes tried by client, and by all clients under "".
+	failed map[string]failures
+}
+
+func Open(ctx context.Context) *Store {
+	s := &Store{pending: make(map[string]pairing), failed: make(map[string]failures)}
+	if config.Application().ConfigPath == "" {
+		return s
+	}
+
+	path := resolvePath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to read devices file")
+		}
+		return s
+	}
+	if err := json.Unmarshal(data, &s.devices); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to parse devices file")
+	}
+	return s
+}
+
+// Devices returns all paired devices, oldest first.
+func (s *Store) Devices() []Device {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.devices)
+}
+
+// Authenticate returns the device the token was issued to.
+func (s *Store) Authenticate(token string) (Device, bool) {
+	if token == "" {
+		return Device{}, false
+	}
+	hash := hashToken(token)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, d := range s.devices {
+		if subtle.ConstantTimeCompare([]byte(d.TokenHash), []byte(hash)) == 1 {
+			return d, true
+		}
+	}
+	return Device{}, false
+}
+
+func (s *Store) Revoke(ctx context.Context, id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := len(s.devices)
+	s.devices = slices.DeleteFunc(s.devices, func(d Device) bool {
+		return d.Id == id
+	})
+	if len(s.devices) == n {
+		return false
+	}
+	s.save(ctx)
+	return true
+}
+
+// add registers a new device and returns its token.
+func (s *Store) add(ctx context.Context, name string) (Device, string, error) {
+	token, err := randomString(32)
+	if err != nil {
+		return Device{}, "", err
+	}
+	id, err := randomString(6)
+	if err != nil {
+		return Device{}, "", err
+	}
+	d := Device{
+		Id:        id,
+		Name:      name,
+		TokenHash: hashToken(token),
+		CreatedAt: time.Now(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.devices = append(s.devices, d)
+	s.save(ctx)
+	return d, token, nil
+}
+
+func (s *Store) save(ctx context.Context) {
+	if config.Application().ConfigPath == "" {
+		log.Ctx(ctx).Warn().Msg("CONFIG_PATH not specified, paired devices will not persist")
+		return
+	}
+	path := resolvePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("error creating config directory")
+		return
+	}
+	data, err := json.MarshalIndent(s.devices, "", "  ")
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to encode devices")
+		return
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to save devices")
+	}
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func resolvePath() string {
+	return filepath.Join(config.Application().ConfigPath, devicesFile)
+}
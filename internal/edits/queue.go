@@ -0,0 +1,294 @@
+package edits
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"slices"
+	"stash-vr/internal/device"
+	"stash-vr/internal/stash"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	retryBase   = 5 * time.Second
+	retryMax    = 5 * time.Minute
+	keepApplied = 50
+	idleWait    = time.Minute
+)
+
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusFailed  Status = "failed"
+	StatusApplied Status = "applied"
+)
+
+// Edit is a change to a scene made in a player, kept until it has been written to Stash.
+type Edit struct {
+	Id      string `json:"id"`
+	SceneId string `json:"sceneId"`
+	Source  string `json:"source"`
+	// Device is the name of the paired device that made the edit.
+	Device      string          `json:"device,omitempty"`
+	Summary     string          `json:"summary"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      Status          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	Error       string          `json:"error,omitempty"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	UpdatedAt   time.Time       `json:"updatedAt"`
+	NextAttempt time.Time       `json:"nextAttempt"`
+}
+
+// HandlerFunc writes an edit to Stash.
+type HandlerFunc func(ctx context.Context, e Edit) error
+
+// Queue holds edits in the order they were made and applies them with the handler registered for their source. Edits
+// of a scene are applied one at a time in order. An edit failing because Stash is unavailable, without having written
+// any of it, is retried with backoff, as is one interrupted by shutdown before it was partially applied. Any other
+// failure stops the edits of that scene until the failed edit is retried or discarded. Edits are saved to CONFIG_PATH
+// so that they survive a restart, or kept in memory only if CONFIG_PATH is not specified.
+type Queue struct {
+	mu       sync.Mutex
+	edits    []Edit
+	handlers map[string]HandlerFunc
+	wake     chan struct{}
+}
+
+func Open(ctx context.Context) *Queue {
+	q := &Queue{handlers: make(map[string]HandlerFunc), wake: make(chan struct{}, 1)}
+	q.edits = load(ctx)
+	if n := q.count(StatusPending); n > 0 {
+		log.Ctx(ctx).Info().Int("pending", n).Msg("Replaying pending edits")
+	}
+	return q
+}
+
+// Handle registers the handler applying edits from source.
+func (q *Queue) Handle(source string, handler HandlerFunc) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[source] = handler
+	q.notify()
+}
+
+// Enqueue adds an edit of a scene. summary describes it to users.
+func (q *Queue) Enqueue(ctx context.Context, sceneId string, source string, summary string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	id, err := randomId()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	e := Edit{
+		Id:        id,
+		SceneId:   sceneId,
+		Source:    source,
+		Device:    deviceName(ctx),
+		Summary:   summary,
+		Payload:   data,
+		Status:    StatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.edits = append(q.edits, e)
+	q.save(ctx)
+	q.notify()
+	return nil
+}
+
+// Edits returns all edits not yet discarded, oldest first.
+func (q *Queue) Edits() []Edit {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return slices.Clone(q.edits)
+}
+
+// Retry makes a pending or failed edit due immediately.
+func (q *Queue) Retry(ctx context.Context, id string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	i := q.index(id)
+	if i < 0 || q.edits[i].Status == StatusApplied {
+		return false
+	}
+	q.edits[i].Status = StatusPending
+	q.edits[i].NextAttempt = time.Time{}
+	q.save(ctx)
+	q.notify()
+	return true
+}
+
+// Discard removes an edit that hasn't been applied.
+func (q *Queue) Discard(ctx context.Context, id string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	i := q.index(id)
+	if i < 0 || q.edits[i].Status == StatusApplied {
+		return false
+	}
+	q.edits = slices.Delete(q.edits, i, i+1)
+	q.save(ctx)
+	q.notify()
+	return true
+}
+
+// Run applies edits as they become due until ctx is done.
+func (q *Queue) Run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.wake:
+		case <-timer.C:
+		}
+
+		wait := idleWait
+		if next := q.applyDue(ctx); !next.IsZero() {
+			wait = time.Until(next)
+		}
+		timer.Reset(wait)
+	}
+}
+
+// applyDue applies all due edits and returns when the next one is due, or zero if none is waiting.
+func (q *Queue) applyDue(ctx context.Context) time.Time {
+	for {
+		e, handler, next := q.nextDue()
+		if handler == nil {
+			return next
+		}
+		logger := log.Ctx(ctx).With().Str("editId", e.Id).Str("videoId", e.SceneId).Str("source", e.Source).Logger()
+		err := handler(logger.WithContext(ctx), e)
+		q.complete(logger.WithContext(ctx), e.Id, err)
+	}
+}
+
+// nextDue returns the first due edit of any scene and its handler. If none is due, handler is nil and next is when the
+// first one will be.
+func (q *Queue) nextDue() (e Edit, handler HandlerFunc, next time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	now := time.Now()
+	blocked := make(map[string]bool)
+	for _, e := range q.edits {
+		if e.Status == StatusApplied || blocked[e.SceneId] {
+			continue
+		}
+		blocked[e.SceneId] = true
+		handler, ok := q.handlers[e.Source]
+		if e.Status != StatusPending || !ok {
+			continue
+		}
+		if !e.NextAttempt.After(now) {
+			return e, handler, time.Time{}
+		}
+		if next.IsZero() || e.NextAttempt.Before(next) {
+			next = e.NextAttempt
+		}
+	}
+	return Edit{}, nil, next
+}
+
+func (q *Queue) complete(ctx context.Context, id string, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	i := q.index(id)
+	if i < 0 {
+		log.Ctx(ctx).Debug().Msg("Edit was discarded while being applied")
+		return
+	}
+	e := &q.edits[i]
+	// A partially applied edit would be applied twice if kept pending, e.g. creating its markers again.
+	if errors.Is(err, context.Canceled) && ctx.Err() != nil && !errors.Is(err, stash.ErrPartiallyApplied) {
+		log.Ctx(ctx).Debug().Msg("Interrupted by shutdown, edit is kept pending")
+		return
+	}
+	e.Attempts++
+	e.UpdatedAt = time.Now()
+	switch {
+	case err == nil:
+		log.Ctx(ctx).Debug().Int("attempts", e.Attempts).Msg("Edit applied")
+		e.Status = StatusApplied
+		e.Error = ""
+		e.Payload = nil
+		q.pruneApplied()
+	case stash.IsRetryable(err):
+		e.NextAttempt = e.UpdatedAt.Add(retryDelay(e.Attempts))
+		e.Error = err.Error()
+		log.Ctx(ctx).Warn().Err(err).Time("nextAttempt", e.NextAttempt).Msg("Stash unavailable, edit will be retried")
+	default:
+		log.Ctx(ctx).Warn().Err(err).Msg("Failed to apply edit")
+		e.Status = StatusFailed
+		e.Error = err.Error()
+	}
+	q.save(ctx)
+}
+
+func (q *Queue) pruneApplied() {
+	applied := q.count(StatusApplied)
+	q.edits = slices.DeleteFunc(q.edits, func(e Edit) bool {
+		if e.Status == StatusApplied && applied > keepApplied {
+			applied--
+			return true
+		}
+		return false
+	})
+}
+
+func (q *Queue) count(status Status) int {
+	n := 0
+	for _, e := range q.edits {
+		if e.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+func (q *Queue) index(id string) int {
+	return slices.IndexFunc(q.edits, func(e Edit) bool {
+		return e.Id == id
+	})
+}
+
+func (q *Queue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func retryDelay(attempts int) time.Duration {
+	return min(retryBase<<min(attempts-1, 10), retryMax)
+}
+
+func randomId() (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func deviceName(ctx context.Context) string {
+	if d, ok := device.FromContext(ctx); ok {
+		return d.Name
+	}
+	return ""
+}
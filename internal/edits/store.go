@@ -0,0 +1,61 @@
+package edits
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"stash-vr/internal/config"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	editsFile = "edits.json"
+)
+
+func load(ctx context.Context) []Edit {
+	if config.Application().ConfigPath == "" {
+		log.Ctx(ctx).Info().Msg("CONFIG_PATH not specified, pending edits will not survive a restart")
+		return nil
+	}
+
+	path := resolvePath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to read edits file")
+		}
+		return nil
+	}
+	var edits []Edit
+	if err := json.Unmarshal(data, &edits); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to parse edits file")
+		return nil
+	}
+	return edits
+}
+
+func (q *Queue) save(ctx context.Context) {
+	if config.Application().ConfigPath == "" {
+		return
+	}
+	path := resolvePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("error creating config directory")
+		return
+	}
+	data, err := json.MarshalIndent(q.edits, "", "  ")
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to encode edits")
+		return
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to save edits")
+	}
+}
+
+func resolvePath() string {
+	return filepath.Join(config.Application().ConfigPath, editsFile)
+}
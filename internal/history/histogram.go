@@ -0,0 +1,107 @@
+package history
+
+import (
+	"cmp"
+	"slices"
+)
+
+// ReplayBuckets is the number of buckets scenes are divided into to find their most replayed parts, shared by the
+// replay heatmap and replay markers so that both show the same parts.
+const ReplayBuckets = 200
+
+// Histogram counts how many times each part of a scene has been watched. The scene is divided into equally sized
+// buckets, each counted once per segment overlapping it.
+type Histogram struct {
+	// Duration of the scene in seconds.
+	Duration float64
+	Counts   []int
+}
+
+func (h Histogram) Max() int {
+	if len(h.Counts) == 0 {
+		return 0
+	}
+	return slices.Max(h.Counts)
+}
+
+// BucketStart returns the position in seconds where bucket i starts.
+func (h Histogram) BucketStart(i int) float64 {
+	return h.Duration * float64(i) / float64(len(h.Counts))
+}
+
+// Peak is a bucket watched more often than its neighbours.
+type Peak struct {
+	Bucket int
+	Count  int
+}
+
+// Peaks returns at most n of the most watched local maxima, at least minDistance buckets apart, that have been
+// watched at least minCount times. Peaks are ordered by position.
+func (h Histogram) Peaks(n int, minDistance int, minCount int) []Peak {
+	candidates := make([]Peak, 0)
+	for i, c := range h.Counts {
+		if c < minCount {
+			continue
+		}
+		if i > 0 && h.Counts[i-1] > c {
+			continue
+		}
+		if i < len(h.Counts)-1 && h.Counts[i+1] > c {
+			continue
+		}
+		candidates = append(candidates, Peak{Bucket: i, Count: c})
+	}
+	slices.SortStableFunc(candidates, func(a, b Peak) int {
+		return cmp.Compare(b.Count, a.Count)
+	})
+
+	peaks := make([]Peak, 0, n)
+	for _, c := range candidates {
+		if len(peaks) == n {
+			break
+		}
+		if slices.ContainsFunc(peaks, func(p Peak) bool {
+			return max(p.Bucket-c.Bucket, c.Bucket-p.Bucket) < minDistance
+		}) {
+			continue
+		}
+		peaks = append(peaks, c)
+	}
+	slices.SortFunc(peaks, func(a, b Peak) int {
+		return cmp.Compare(a.Bucket, b.Bucket)
+	})
+	return peaks
+}
+
+// Histogram returns how often each of the given number of buckets of a scene has been watched. ok is false if the
+// scene has no watch history.
+func (s *Store) Histogram(sceneId string, buckets int) (h Histogram, ok bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	h.Counts = make([]int, buckets)
+	for _, r := range s.records {
+		if r.SceneId != sceneId || r.Duration <= 0 {
+			continue
+		}
+		h.Duration = r.Duration
+		for _, seg := range r.Segments {
+			first := int(seg.From / r.Duration * float64(buckets))
+			last := int(seg.To / r.Duration * float64(buckets))
+			for i := max(first, 0); i <= min(last, buckets-1); i++ {
+				h.Counts[i]++
+			}
+			ok = true
+		}
+	}
+	return h, ok
+}
+
+// HasHistory reports whether the scene has been watched.
+func (s *Store) HasHistory(sceneId string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.ContainsFunc(s.records, func(r Record) bool {
+		return r.SceneId == sceneId && len(r.Segments) > 0
+	})
+}
@@ -0,0 +1,66 @@
+package history
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// Record is one viewing of a scene, from first play until the player closes it or moves on to another scene.
+type Record struct {
+	SceneId    string    `json:"sceneId"`
+	Title      string    `json:"title"`
+	Studio     string    `json:"studio,omitempty"`
+	Performers []string  `json:"performers,omitempty"`
+	Tags       []string  `json:"tags,omitempty"`
+	Device     string    `json:"device,omitempty"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	// Duration of the scene in seconds.
+	Duration float64   `json:"duration"`
+	Segments []Segment `json:"segments"`
+}
+
+// Segment is a continuous part of the scene timeline played at a single speed. Positions are in seconds.
+type Segment struct {
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+	Speed float64 `json:"speed"`
+}
+
+// WatchTime is the time spent watching, i.e. positions covered adjusted for playback speed.
+func (r Record) WatchTime() time.Duration {
+	var total time.Duration
+	for _, s := range r.Segments {
+		speed := s.Speed
+		if speed <= 0 {
+			speed = 1
+		}
+		total += time.Duration((s.To - s.From) / speed * float64(time.Second))
+	}
+	return total
+}
+
+// Completion is the fraction of the scene covered by at least one segment.
+func (r Record) Completion() float64 {
+	if r.Duration <= 0 || len(r.Segments) == 0 {
+		return 0
+	}
+	segments := slices.Clone(r.Segments)
+	slices.SortFunc(segments, func(a, b Segment) int {
+		return cmp.Compare(a.From, b.From)
+	})
+
+	covered := 0.0
+	from, to := segments[0].From, segments[0].To
+	for _, s := range segments[1:] {
+		if s.From <= to {
+			to = max(to, s.To)
+			continue
+		}
+		covered += to - from
+		from, to = s.From, s.To
+	}
+	covered += to - from
+	return min(covered/r.Duration, 1)
+}
@@ -0,0 +1,102 @@
+package history
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"stash-vr/internal/config"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	historyFile = "history.jsonl"
+)
+
+// Store holds the watch history. Records are appended to a JSON Lines file in CONFIG_PATH, or kept in memory only
+// if CONFIG_PATH is not specified.
+type Store struct {
+	mu      sync.RWMutex
+	records []Record
+}
+
+func Open(ctx context.Context) *Store {
+	s := &Store{}
+	if config.Application().ConfigPath == "" {
+		log.Ctx(ctx).Info().Msg("CONFIG_PATH not specified, watch history will not persist")
+		return s
+	}
+
+	path := resolvePath()
+	f, err := os.Open(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to open watch history")
+		}
+		return s
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("skipping malformed watch history record")
+			continue
+		}
+		s.records = append(s.records, r)
+	}
+	if err := scanner.Err(); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to read watch history")
+	}
+
+	log.Ctx(ctx).Debug().Int("records", len(s.records)).Msg("Loaded watch history")
+	return s
+}
+
+func (s *Store) Append(ctx context.Context, r Record) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, r)
+
+	if config.Application().ConfigPath == "" {
+		return
+	}
+	if err := appendLine(resolvePath(), r); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to save watch history record")
+	}
+}
+
+// Records returns all records, oldest first.
+func (s *Store) Records() []Record {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.records)
+}
+
+func appendLine(path string, r Record) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+func resolvePath() string {
+	return filepath.Join(config.Application().ConfigPath, historyFile)
+}
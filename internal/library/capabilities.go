@@ -0,0 +1,42 @@
+package library
+
+import (
+	"context"
+	"stash-vr/internal/stash"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DetectCapabilities inspects the schema of Stash to select queries and mutations compatible with its version.
+func (libraryService *Service) DetectCapabilities(ctx context.Context) error {
+	c, err := stash.DetectCapabilities(ctx, libraryService.StashClient)
+	if err != nil {
+		return err
+	}
+
+	libraryService.muCapabilities.Lock()
+	libraryService.capabilities = &c
+	libraryService.muCapabilities.Unlock()
+
+	for _, l := range c.Limitations() {
+		log.Ctx(ctx).Warn().Msg(l)
+	}
+	log.Ctx(ctx).Debug().Interface("capabilities", c).Msg("Detected Stash capabilities")
+	return nil
+}
+
+// Capabilities returns the capabilities of Stash, detecting them if that hasn't succeeded yet. The latest schema is
+// assumed if Stash can't be inspected.
+func (libraryService *Service) Capabilities(ctx context.Context) stash.Capabilities {
+	libraryService.muCapabilities.Lock()
+	c := libraryService.capabilities
+	libraryService.muCapabilities.Unlock()
+	if c != nil {
+		return *c
+	}
+	if err := libraryService.DetectCapabilities(ctx); err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("Failed to detect Stash capabilities, assuming latest")
+		return stash.LatestCapabilities
+	}
+	return libraryService.Capabilities(ctx)
+}
@@ -0,0 +1,671 @@
+package library
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"stash-vr/internal/audit"
+	"stash-vr/internal/config"
+	"stash-vr/internal/stash"
+	"stash-vr/internal/stash/gql"
+	"stash-vr/internal/util"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ChangeSet collects changes to a scene and diffs them against the scene when applied, so that only what actually
+// changed is written.
+type ChangeSet struct {
+	libraryService *Service
+	scene          *VideoData
+
+	rating100  *int
+	ratingSet  bool
+	organized  *bool
+	tags       []string
+	performers []string
+	studio     string
+	groups     []SceneGroup
+	fields     map[Field]string
+
+	createMarkers  []MarkerDto
+	updateMarkers  []MarkerDto
+	destroyMarkers []string
+
+	oDelta         int
+	playCountDelta int
+
+	warnings []error
+}
+
+// NewChangeSet returns an empty change set for the scene with id, fetched fresh so that tags and other lists written
+// as a whole don't drop what was added in Stash since the scene was cached.
+func (libraryService *Service) NewChangeSet(ctx context.Context, id string) (*ChangeSet, error) {
+	vd, err := libraryService.GetScene(ctx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	return &ChangeSet{
+		libraryService: libraryService,
+		scene:          vd,
+		tags:           tagNames(vd.StashTags),
+		performers:     performerNames(vd),
+		studio:         studioName(vd),
+		groups:         slices.Clone(vd.Groups),
+	}, nil
+}
+
+func studioName(vd *VideoData) string {
+	if vd.SceneParts.Studio == nil {
+		return ""
+	}
+	return vd.SceneParts.Studio.Name
+}
+
+func performerNames(vd *VideoData) []string {
+	names := make([]string, len(vd.SceneParts.Performers))
+	for i, p := range vd.SceneParts.Performers {
+		names[i] = p.Name
+	}
+	return names
+}
+
+func tagNames(tags []Tag) []string {
+	names := make([]string, len(tags))
+	for i, t := range tags {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// Tags returns the names of the tags the scene will have.
+func (cs *ChangeSet) Tags() []string {
+	return slices.Clone(cs.tags)
+}
+
+func (cs *ChangeSet) SetRating(rating5 *float32) {
+	cs.rating100 = nil
+	if rating5 != nil {
+		cs.rating100 = util.Ptr(int(*rating5 * 20))
+	}
+	cs.ratingSet = true
+}
+
+func (cs *ChangeSet) SetOrganized(organized bool) {
+	cs.organized = &organized
+}
+
+// SetTags sets the names of all tags of the scene, tags that don't exist are created.
+func (cs *ChangeSet) SetTags(names []string) {
+	cs.tags = slices.Compact(slices.Sorted(slices.Values(names)))
+}
+
+// Performers returns the names of the performers the scene will have.
+func (cs *ChangeSet) Performers() []string {
+	return slices.Clone(cs.performers)
+}
+
+// SetPerformers sets the names of all performers of the scene. Names are matched against names and aliases of
+// performers in Stash, performers that don't exist are created if CREATE_PERFORMERS is set.
+func (cs *ChangeSet) SetPerformers(names []string) {
+	cs.performers = slices.Compact(slices.Sorted(slices.Values(names)))
+}
+
+// Studio returns the name of the studio the scene will have, empty for none.
+func (cs *ChangeSet) Studio() string {
+	return cs.studio
+}
+
+// SetStudio sets the studio of the scene by name, matched against names and aliases of studios in Stash. An empty name
+// removes the studio.
+func (cs *ChangeSet) SetStudio(name string) {
+	cs.studio = name
+}
+
+// Groups returns the groups the scene will belong to.
+func (cs *ChangeSet) Groups() []SceneGroup {
+	return slices.Clone(cs.groups)
+}
+
+// SetGroups sets the groups the scene belongs to, matched by name against groups in Stash. If a group is given more
+// than once the last one is used.
+func (cs *ChangeSet) SetGroups(groups []SceneGroup) {
+	cs.groups = nil
+	for _, g := range slices.Backward(groups) {
+		if !slices.ContainsFunc(cs.groups, func(other SceneGroup) bool {
+			return other.Name == g.Name
+		}) {
+			cs.groups = append(cs.groups, g)
+		}
+	}
+	slices.SortFunc(cs.groups, func(a, b SceneGroup) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+}
+
+// SetField sets a text field of the scene, an empty value clears it. The value is validated, e.g. dates must be
+// yyyy-mm-dd.
+func (cs *ChangeSet) SetField(f Field, value string) error {
+	if err := validateField(f, value); err != nil {
+		return err
+	}
+	if cs.fields == nil {
+		cs.fields = make(map[Field]string)
+	}
+	cs.fields[f] = value
+	return nil
+}
+
+// Warnings returns the changes that were skipped when applied, e.g. performers that don't exist.
+func (cs *ChangeSet) Warnings() []error {
+	return slices.Clone(cs.warnings)
+}
+
+// SetFavorite adds or removes FAVORITE_TAG.
+func (cs *ChangeSet) SetFavorite(ctx context.Context, isFavorite bool) {
+	favoriteTagName := config.Application().FavoriteTag
+	if favoriteTagName == "" {
+		log.Ctx(ctx).Info().Msg("Sync favorite requested but FAVORITE_TAG is empty, ignoring request")
+		return
+	}
+	tags := slices.DeleteFunc(cs.Tags(), func(name string) bool {
+		return name == favoriteTagName
+	})
+	if isFavorite {
+		tags = append(tags, favoriteTagName)
+	}
+	cs.SetTags(tags)
+}
+
+func (cs *ChangeSet) IncrementO() {
+	cs.oDelta++
+}
+
+func (cs *ChangeSet) DecrementO() {
+	cs.oDelta--
+}
+
+func (cs *ChangeSet) IncrementPlayCount() {
+	cs.playCountDelta++
+}
+
+func (cs *ChangeSet) DecrementPlayCount() {
+	cs.playCountDelta--
+}
+
+// MergeMarkers merges the markers edited by a client into the markers of the scene. baseMarkers are the markers as
+// they were served to the client, only markers the client changed are written and markers created or changed in Stash
+// meanwhile are kept.
+func (cs *ChangeSet) MergeMarkers(ctx context.Context, incomingMarkers []MarkerDto, baseMarkers []MarkerDto) {
+	findMarker := func(markers []MarkerDto, markerId string) (MarkerDto, bool) {
+		i := slices.IndexFunc(markers, func(m MarkerDto) bool {
+			return m.MarkerId == markerId
+		})
+		if i < 0 {
+			return MarkerDto{}, false
+		}
+		return markers[i], true
+	}
+
+	existingMarkers := make([]MarkerDto, len(cs.scene.SceneParts.Scene_markers))
+	for i, sm := range cs.scene.SceneParts.Scene_markers {
+		existingMarkers[i] = MarkerOf(sm)
+	}
+
+	cs.createMarkers, cs.updateMarkers, cs.destroyMarkers = nil, nil, nil
+
+	for _, existing := range existingMarkers {
+		if _, served := findMarker(baseMarkers, existing.MarkerId); !served {
+			continue
+		}
+		if _, kept := findMarker(incomingMarkers, existing.MarkerId); !kept {
+			cs.destroyMarkers = append(cs.destroyMarkers, existing.MarkerId)
+		}
+	}
+
+	for _, incoming := range incomingMarkers {
+		base, served := findMarker(baseMarkers, incoming.MarkerId)
+		existing, exists := findMarker(existingMarkers, incoming.MarkerId)
+		switch {
+		case !served:
+			cs.createMarkers = append(cs.createMarkers, incoming)
+		case !exists:
+			log.Ctx(ctx).Warn().Str("markerId", incoming.MarkerId).Msg("Marker was deleted in Stash, ignoring edit")
+		case sameMarker(incoming, base):
+		case !sameMarker(existing, base):
+			log.Ctx(ctx).Warn().Str("markerId", incoming.MarkerId).Msg("Marker was changed in Stash, rejecting conflicting edit")
+		default:
+			cs.updateMarkers = append(cs.updateMarkers, incoming)
+		}
+	}
+}
+
+// Apply writes the changes to Stash with at most two requests: scalar fields, tags, performers, studio and groups with a
+// single sceneUpdate, then marker and counter changes with a single batch of aliased mutations. Each change is recorded
+// in the audit log once written. Stash executes the mutations of the batch one by one and can't roll them back: if some
+// fail after a change that can't safely be repeated was written, i.e. a created marker or a counter change, the error
+// wraps stash.ErrPartiallyApplied.
+func (cs *ChangeSet) Apply(ctx context.Context) error {
+	cs.dropDenied(ctx)
+
+	input, err := cs.sceneUpdateInput(ctx)
+	if err != nil {
+		return err
+	}
+	if input != nil {
+		if _, err := gql.SceneUpdate(ctx, cs.libraryService.StashClient, input); err != nil {
+			return fmt.Errorf("SceneUpdate: %w", err)
+		}
+		cs.recordUpdate(ctx, input)
+	}
+	return cs.applyBatch(ctx)
+}
+
+func partiallyApplied(partial bool, err error) error {
+	if partial {
+		return fmt.Errorf("%w: %w", stash.ErrPartiallyApplied, err)
+	}
+	return err
+}
+
+// sceneUpdateInput returns the input of the sceneUpdate for scalar fields, tags, performers, studio and groups, nil if
+// none changed.
+func (cs *ChangeSet) sceneUpdateInput(ctx context.Context) (*gql.SceneUpdateInput, error) {
+	sp := cs.scene.SceneParts
+	input := &gql.SceneUpdateInput{Id: cs.scene.Id()}
+	changed := false
+
+	if cs.ratingSet && !util.PtrEqual(cs.rating100, sp.Rating100) {
+		input.Rating100 = &cs.rating100
+		changed = true
+	}
+	if cs.organized != nil && *cs.organized != sp.Organized {
+		input.Organized = cs.organized
+		changed = true
+	}
+	if !util.UnorderedEqual(cs.tags, tagNames(cs.scene.StashTags)) {
+		tagIds := make([]string, len(cs.tags))
+		for i, name := range cs.tags {
+			tagId, err := cs.tagId(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			tagIds[i] = tagId
+		}
+		input.Tag_ids = &tagIds
+		changed = true
+	}
+	if !util.UnorderedEqual(cs.performers, performerNames(cs.scene)) {
+		performerIds, err := cs.performerIds(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !util.UnorderedEqual(cs.performers, performerNames(cs.scene)) {
+			input.Performer_ids = &performerIds
+			changed = true
+		}
+	}
+	if cs.studio != studioName(cs.scene) {
+		studioId, err := cs.studioId(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if cs.studio != studioName(cs.scene) {
+			input.Studio_id = &studioId
+			changed = true
+		}
+	}
+	groupsChanged, err := cs.groupsInput(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	changed = changed || groupsChanged
+	for _, f := range cs.changedFields() {
+		value := util.NilIfEmpty(cs.fields[f])
+		switch f {
+		case FieldTitle:
+			input.Title = &value
+		case FieldDate:
+			input.Date = &value
+		case FieldCode:
+			input.Code = &value
+		case FieldDetails:
+			input.Details = &value
+		}
+		changed = true
+	}
+
+	if !changed {
+		return nil, nil
+	}
+	return input, nil
+}
+
+// groupsInput sets the groups of the scene in input, as movies for Stash versions before groups replaced movies, and
+// reports whether they changed.
+func (cs *ChangeSet) groupsInput(ctx context.Context, input *gql.SceneUpdateInput) (bool, error) {
+	if sameGroups(cs.groups, cs.scene.Groups) {
+		return false, nil
+	}
+	sceneGroups := cs.libraryService.Capabilities(ctx).SceneGroups
+	if err := cs.resolveGroups(ctx, sceneGroups); err != nil {
+		return false, err
+	}
+	if sameGroups(cs.groups, cs.scene.Groups) {
+		return false, nil
+	}
+
+	if sceneGroups {
+		groups := make([]*gql.SceneGroupInput, len(cs.groups))
+		for i, g := range cs.groups {
+			groups[i] = &gql.SceneGroupInput{Group_id: g.Id, Scene_index: g.SceneIndex}
+		}
+		input.Groups = &groups
+	} else {
+		movies := make([]*gql.SceneMovieInput, len(cs.groups))
+		for i, g := range cs.groups {
+			movies[i] = &gql.SceneMovieInput{Movie_id: g.Id, Scene_index: g.SceneIndex}
+		}
+		input.Movies = &movies
+	}
+	return true, nil
+}
+
+// batchWrite is a mutation of the batch sent by applyBatch. record is called with its result once it's applied.
+type batchWrite struct {
+	alias string
+	// repeatable tells whether the mutation can safely be applied again when the change set is retried.
+	repeatable bool
+	record     func(result json.RawMessage)
+}
+
+// applyBatch writes the updated, destroyed and created markers and the counter changes with a single request, in that
+// order, and records those that were applied.
+func (cs *ChangeSet) applyBatch(ctx context.Context) error {
+	auditLog := cs.libraryService.auditLog
+	id := cs.scene.Id()
+	sp := cs.scene.SceneParts
+
+	var batch stash.Batch
+	var writes []batchWrite
+
+	for _, m := range cs.updateMarkers {
+		tagId, err := cs.tagId(ctx, m.PrimaryTagName)
+		if err != nil {
+			return fmt.Errorf("failed to find or create primary tag for marker: %w", err)
+		}
+		alias := batch.Add("sceneMarkerUpdate", "input", "SceneMarkerUpdateInput!", map[string]any{
+			"id":             m.MarkerId,
+			"primary_tag_id": tagId,
+			"seconds":        m.StartSecond,
+			"end_seconds":    m.EndSecond,
+			"title":          m.Title,
+		}, "{id}")
+		writes = append(writes, batchWrite{alias: alias, repeatable: true, record: func(json.RawMessage) {
+			auditLog.Record(ctx, id, audit.KindMarkerUpdate, cs.existingMarker(m.MarkerId), m)
+		}})
+	}
+	if len(cs.destroyMarkers) > 0 {
+		alias := batch.Add("sceneMarkersDestroy", "ids", "[ID!]!", cs.destroyMarkers, "")
+		writes = append(writes, batchWrite{alias: alias, repeatable: true, record: func(json.RawMessage) {
+			for _, markerId := range cs.destroyMarkers {
+				auditLog.Record(ctx, id, audit.KindMarkerDestroy, cs.existingMarker(markerId), nil)
+			}
+		}})
+	}
+	for _, m := range cs.createMarkers {
+		tagId, err := cs.tagId(ctx, m.PrimaryTagName)
+		if err != nil {
+			return fmt.Errorf("failed to find or create primary tag for marker: %w", err)
+		}
+		alias := batch.Add("sceneMarkerCreate", "input", "SceneMarkerCreateInput!", map[string]any{
+			"scene_id":       id,
+			"primary_tag_id": tagId,
+			"seconds":        m.StartSecond,
+			"end_seconds":    m.EndSecond,
+			"title":          m.Title,
+		}, "{id}")
+		writes = append(writes, batchWrite{alias: alias, record: func(result json.RawMessage) {
+			var created struct {
+				Id string `json:"id"`
+			}
+			if err := json.Unmarshal(result, &created); err == nil {
+				m.MarkerId = created.Id
+			}
+			auditLog.Record(ctx, id, audit.KindMarkerCreate, nil, m)
+		}})
+	}
+	var oDelta, playCountDelta int
+	writes = append(writes, cs.counterWrites(ctx, &batch, &oDelta, &playCountDelta)...)
+
+	results, err := batch.Do(ctx, cs.libraryService.StashClient)
+	var batchErr *stash.BatchError
+	if err != nil && !errors.As(err, &batchErr) {
+		// Stash may have applied the batch before it was interrupted.
+		interrupted := errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+		return partiallyApplied(interrupted && slices.ContainsFunc(writes, func(w batchWrite) bool {
+			return !w.repeatable
+		}), err)
+	}
+	partial := false
+	for _, w := range writes {
+		if batchErr != nil && batchErr.Failed[w.alias] {
+			continue
+		}
+		w.record(results[w.alias])
+		partial = partial || !w.repeatable
+	}
+	if oDelta != 0 {
+		before := util.Deref(sp.O_counter)
+		auditLog.Record(ctx, id, audit.KindOCount, before, max(before+oDelta, 0))
+	}
+	if playCountDelta != 0 {
+		before := util.Deref(sp.Play_count)
+		auditLog.Record(ctx, id, audit.KindPlayCount, before, max(before+playCountDelta, 0))
+	}
+	if err != nil {
+		return partiallyApplied(partial, err)
+	}
+	return nil
+}
+
+// counterWrites adds a mutation for each step of the o-count and play count changes to batch. oDelta and playCountDelta
+// count the steps applied.
+func (cs *ChangeSet) counterWrites(ctx context.Context, batch *stash.Batch, oDelta *int, playCountDelta *int) []batchWrite {
+	c := cs.libraryService.Capabilities(ctx)
+	id := cs.scene.Id()
+
+	var writes []batchWrite
+	add := func(mutation string, selection string, delta *int, step int) {
+		alias := batch.Add(mutation, "id", "ID!", id, selection)
+		writes = append(writes, batchWrite{alias: alias, record: func(json.RawMessage) {
+			*delta += step
+		}})
+	}
+
+	for range util.Abs(cs.oDelta) {
+		switch {
+		case c.OHistory && cs.oDelta > 0:
+			add("sceneAddO", "{count}", oDelta, 1)
+		case c.OHistory:
+			add("sceneDeleteO", "{count}", oDelta, -1)
+		case cs.oDelta > 0:
+			add("sceneIncrementO", "", oDelta, 1)
+		default:
+			add("sceneDecrementO", "", oDelta, -1)
+		}
+	}
+	for range util.Abs(cs.playCountDelta) {
+		switch {
+		case c.PlayHistory && cs.playCountDelta > 0:
+			add("sceneAddPlay", "{count}", playCountDelta, 1)
+		case c.PlayHistory:
+			add("sceneDeletePlay", "{count}", playCountDelta, -1)
+		case cs.playCountDelta > 0:
+			add("sceneIncrementPlayCount", "", playCountDelta, 1)
+		default:
+			log.Ctx(ctx).Trace().Err(stash.ErrUnsupported).Msg("Skipping play count decrement")
+		}
+	}
+	return writes
+}
+
+func (cs *ChangeSet) recordUpdate(ctx context.Context, input *gql.SceneUpdateInput) {
+	auditLog := cs.libraryService.auditLog
+	id := cs.scene.Id()
+	sp := cs.scene.SceneParts
+	if input.Rating100 != nil {
+		auditLog.Record(ctx, id, audit.KindRating, sp.Rating100, cs.rating100)
+	}
+	if input.Organized != nil {
+		auditLog.Record(ctx, id, audit.KindOrganized, sp.Organized, *cs.organized)
+	}
+	if input.Tag_ids != nil {
+		auditLog.Record(ctx, id, audit.KindTags, slices.Sorted(slices.Values(tagNames(cs.scene.StashTags))), cs.tags)
+	}
+	if input.Performer_ids != nil {
+		auditLog.Record(ctx, id, audit.KindPerformers, slices.Sorted(slices.Values(performerNames(cs.scene))), cs.performers)
+	}
+	if input.Studio_id != nil {
+		auditLog.Record(ctx, id, audit.KindStudio, studioName(cs.scene), cs.studio)
+	}
+	if input.Groups != nil || input.Movies != nil {
+		auditLog.Record(ctx, id, audit.KindGroups, cs.scene.Groups, cs.groups)
+	}
+	for _, f := range cs.changedFields() {
+		auditLog.Record(ctx, id, fieldKinds[f], cs.scene.Field(f), cs.fields[f])
+	}
+}
+
+// existingMarker returns the marker of the scene with markerId as it was before the changes.
+func (cs *ChangeSet) existingMarker(markerId string) MarkerDto {
+	for _, sm := range cs.scene.SceneParts.Scene_markers {
+		if sm.Id == markerId {
+			return MarkerOf(sm)
+		}
+	}
+	return MarkerDto{MarkerId: markerId}
+}
+
+// performerIds returns the ids of the performers of cs and replaces names matched by alias with the name of the
+// performer. Performers that can't be found are dropped and added to the warnings.
+func (cs *ChangeSet) performerIds(ctx context.Context) ([]string, error) {
+	var ids, names []string
+	for _, name := range cs.performers {
+		var id string
+		if i := slices.IndexFunc(cs.scene.SceneParts.Performers, func(p *gql.ScenePartsPerformersPerformer) bool {
+			return p.Name == name
+		}); i >= 0 {
+			id = cs.scene.SceneParts.Performers[i].Id
+		} else {
+			var err error
+			id, name, err = stash.FindOrCreatePerformer(ctx, cs.libraryService.StashClient, name, config.Application().CreatePerformers)
+			if errors.Is(err, stash.ErrPerformerNotFound) {
+				log.Ctx(ctx).Warn().Err(err).Msg("Skipping performer, set CREATE_PERFORMERS to create it")
+				cs.warnings = append(cs.warnings, err)
+				continue
+			}
+			if errors.Is(err, stash.ErrAmbiguous) {
+				log.Ctx(ctx).Warn().Err(err).Msg("Skipping performer")
+				cs.warnings = append(cs.warnings, err)
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+			names = append(names, name)
+		}
+	}
+	cs.SetPerformers(names)
+	return ids, nil
+}
+
+// studioId returns the id of the studio of cs, nil for none, and replaces a name matched by alias with the name of the
+// studio. A studio that can't be found is dropped and added to the warnings.
+func (cs *ChangeSet) studioId(ctx context.Context) (*string, error) {
+	if cs.studio == "" {
+		return nil, nil
+	}
+	id, name, err := stash.FindStudio(ctx, cs.libraryService.StashClient, cs.studio)
+	if errors.Is(err, stash.ErrStudioNotFound) || errors.Is(err, stash.ErrAmbiguous) {
+		log.Ctx(ctx).Warn().Err(err).Msg("Skipping studio")
+		cs.warnings = append(cs.warnings, err)
+		cs.studio = studioName(cs.scene)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cs.studio = name
+	return &id, nil
+}
+
+// resolveGroups looks up the ids of groups added by name, movies if sceneGroups is false, and replaces their names with
+// the names in Stash. Groups that can't be found are dropped and added to the warnings.
+func (cs *ChangeSet) resolveGroups(ctx context.Context, sceneGroups bool) error {
+	var groups []SceneGroup
+	for _, g := range cs.groups {
+		if g.Id == "" {
+			if i := slices.IndexFunc(cs.scene.Groups, func(existing SceneGroup) bool {
+				return existing.Name == g.Name
+			}); i >= 0 {
+				g.Id = cs.scene.Groups[i].Id
+			} else {
+				var err error
+				g.Id, g.Name, err = stash.FindGroup(ctx, cs.libraryService.StashClient, g.Name, sceneGroups)
+				if errors.Is(err, stash.ErrGroupNotFound) || errors.Is(err, stash.ErrAmbiguous) {
+					log.Ctx(ctx).Warn().Err(err).Msg("Skipping group")
+					cs.warnings = append(cs.warnings, err)
+					continue
+				}
+				if err != nil {
+					return err
+				}
+			}
+		}
+		groups = append(groups, g)
+	}
+	cs.SetGroups(groups)
+	return nil
+}
+
+// sameGroups reports whether a and b contain the same groups with the same scene indexes, in any order.
+func sameGroups(a []SceneGroup, b []SceneGroup) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, g := range a {
+		if !slices.ContainsFunc(b, func(other SceneGroup) bool {
+			return other.Name == g.Name && util.PtrEqual(other.SceneIndex, g.SceneIndex)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// tagId finds a tag by name among the tags of the scene and all tags loaded, creating it in Stash if necessary.
+func (cs *ChangeSet) tagId(ctx context.Context, name string) (string, error) {
+	for _, t := range cs.scene.StashTags {
+		if t.Name == name {
+			return t.Id, nil
+		}
+	}
+	for _, sm := range cs.scene.SceneParts.Scene_markers {
+		if sm.Primary_tag.Name == name {
+			return sm.Primary_tag.Id, nil
+		}
+	}
+	for _, t := range cs.libraryService.tags() {
+		if t.Name == name {
+			return t.Id, nil
+		}
+	}
+	return stash.FindOrCreateTag(ctx, cs.libraryService.StashClient, name)
+}
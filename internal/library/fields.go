@@ -0,0 +1,80 @@
+package library
+
+import (
+	"errors"
+	"fmt"
+	"stash-vr/internal/audit"
+	"stash-vr/internal/util"
+	"time"
+)
+
+// Field is a text field of a scene that can be edited, named as in sceneUpdate.
+type Field string
+
+const (
+	FieldTitle   Field = "title"
+	FieldDate    Field = "date"
+	FieldCode    Field = "code"
+	FieldDetails Field = "details"
+)
+
+// Fields lists all text fields of a scene that can be edited.
+var Fields = []Field{FieldTitle, FieldDate, FieldCode, FieldDetails}
+
+var fieldKinds = map[Field]audit.Kind{
+	FieldTitle:   audit.KindTitle,
+	FieldDate:    audit.KindDate,
+	FieldCode:    audit.KindCode,
+	FieldDetails: audit.KindDetails,
+}
+
+var ErrInvalidField = errors.New("invalid value")
+
+// dateLayouts are the full and partial dates accepted by Stash.
+var dateLayouts = []string{time.DateOnly, "2006-01", "2006"}
+
+// Field returns the value of a text field of the scene as stored in Stash, empty if unset. Unlike Title it doesn't
+// fall back to the file name.
+func (vd VideoData) Field(f Field) string {
+	sp := vd.SceneParts
+	switch f {
+	case FieldTitle:
+		return util.Deref(sp.Title)
+	case FieldDate:
+		return util.Deref(sp.Date)
+	case FieldCode:
+		return util.Deref(sp.Code)
+	case FieldDetails:
+		return util.Deref(sp.Details)
+	}
+	return ""
+}
+
+func validateField(f Field, value string) error {
+	switch f {
+	case FieldTitle, FieldCode, FieldDetails:
+		return nil
+	case FieldDate:
+		if value == "" {
+			return nil
+		}
+		for _, layout := range dateLayouts {
+			if _, err := time.Parse(layout, value); err == nil && len(value) == len(layout) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w for %s: %q, expected yyyy-mm-dd", ErrInvalidField, f, value)
+	}
+	return fmt.Errorf("%w: unknown field %s", ErrInvalidField, f)
+}
+
+// changedFields returns the text fields set to a value that differs from the one in Stash.
+func (cs *ChangeSet) changedFields() []Field {
+	var changed []Field
+	for _, f := range Fields {
+		if value, ok := cs.fields[f]; ok && value != cs.scene.Field(f) {
+			changed = append(changed, f)
+		}
+	}
+	return changed
+}
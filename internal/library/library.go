@@ -4,17 +4,34 @@ import (
 	"github.com/Khan/genqlient/graphql"
 	"golang.org/x/sync/singleflight"
 	"maps"
+	"net/http"
+	"stash-vr/internal/audit"
+	"stash-vr/internal/stash"
+	"stash-vr/internal/trash"
 	"sync"
 )
 
 type Service struct {
 	StashClient graphql.Client
-	vdCache     map[string]*VideoData
-	muVdCache   sync.RWMutex
-	single      singleflight.Group
-	Stats       Stats
+	// HttpClient is used for requests to Stash outside GraphQL, e.g. images and media.
+	HttpClient *http.Client
+	vdCache    map[string]*VideoData
+	muVdCache  sync.RWMutex
+	single     singleflight.Group
+	Stats      Stats
+	sections   []Section
 
-	tagCache map[string]*Tag
+	// tagCache is replaced as a whole by LoadTags and not modified after, read it through tags.
+	tagCache   map[string]*Tag
+	muTagCache sync.RWMutex
+
+	muCapabilities sync.Mutex
+	capabilities   *stash.Capabilities
+
+	auditLog   *audit.Log
+	trashStore *trash.Store
+	// muRevert makes reverts one at a time, so that a change can't be reverted twice.
+	muRevert sync.Mutex
 }
 
 func (libraryService *Service) snapshot() map[string]*VideoData {
@@ -23,10 +40,28 @@ func (libraryService *Service) snapshot() map[string]*VideoData {
 	return maps.Clone(libraryService.vdCache)
 }
 
-func NewService(client graphql.Client) *Service {
+// fetchedSnapshot is like snapshot but without scenes that haven't been fetched yet.
+func (libraryService *Service) fetchedSnapshot() map[string]*VideoData {
+	vds := libraryService.snapshot()
+	maps.DeleteFunc(vds, func(_ string, vd *VideoData) bool {
+		return vd == nil
+	})
+	return vds
+}
+
+func (libraryService *Service) tags() map[string]*Tag {
+	libraryService.muTagCache.RLock()
+	defer libraryService.muTagCache.RUnlock()
+	return libraryService.tagCache
+}
+
+func NewService(client graphql.Client, httpClient *http.Client, auditLog *audit.Log, trashStore *trash.Store) *Service {
 	return &Service{
 		StashClient: client,
+		HttpClient:  httpClient,
 		vdCache:     make(map[string]*VideoData),
+		auditLog:    auditLog,
+		trashStore:  trashStore,
 	}
 }
 
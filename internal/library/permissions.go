@@ -0,0 +1,111 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"stash-vr/internal/config"
+	"stash-vr/internal/util"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ErrWriteDenied is returned for changes to Stash denied by READ_ONLY or DENY_WRITE.
+var ErrWriteDenied = errors.New("change to Stash denied by READ_ONLY or DENY_WRITE")
+
+// dropDenied discards the changes denied by READ_ONLY or DENY_WRITE.
+func (cs *ChangeSet) dropDenied(ctx context.Context) {
+	cfg := config.Application()
+	var denied []string
+
+	if cs.ratingSet && !cfg.CanWrite(config.WriteRating) {
+		cs.ratingSet = false
+		denied = append(denied, config.WriteRating)
+	}
+	if cs.organized != nil && !cfg.CanWrite(config.WriteOrganized) {
+		cs.organized = nil
+		denied = append(denied, config.WriteOrganized)
+	}
+	if tags, deniedTags := cs.allowedTags(cfg); len(deniedTags) > 0 {
+		cs.tags = tags
+		denied = append(denied, deniedTags...)
+	}
+	if !cfg.CanWrite(config.WritePerformers) && !util.UnorderedEqual(cs.performers, performerNames(cs.scene)) {
+		cs.performers = performerNames(cs.scene)
+		denied = append(denied, config.WritePerformers)
+	}
+	if !cfg.CanWrite(config.WriteStudio) && cs.studio != studioName(cs.scene) {
+		cs.studio = studioName(cs.scene)
+		denied = append(denied, config.WriteStudio)
+	}
+	if !cfg.CanWrite(config.WriteGroups) && !sameGroups(cs.groups, cs.scene.Groups) {
+		cs.groups = slices.Clone(cs.scene.Groups)
+		denied = append(denied, config.WriteGroups)
+	}
+	if len(cs.changedFields()) > 0 && !cfg.CanWrite(config.WriteMetadata) {
+		cs.fields = nil
+		denied = append(denied, config.WriteMetadata)
+	}
+	if len(cs.createMarkers)+len(cs.updateMarkers)+len(cs.destroyMarkers) > 0 && !cfg.CanWrite(config.WriteMarkers) {
+		cs.createMarkers, cs.updateMarkers, cs.destroyMarkers = nil, nil, nil
+		denied = append(denied, config.WriteMarkers)
+	}
+	if cs.oDelta != 0 && !cfg.CanWrite(config.WriteOCount) {
+		cs.oDelta = 0
+		denied = append(denied, config.WriteOCount)
+	}
+	if cs.playCountDelta != 0 && !cfg.CanWrite(config.WritePlay) {
+		cs.playCountDelta = 0
+		denied = append(denied, config.WritePlay)
+	}
+
+	if len(denied) > 0 {
+		log.Ctx(ctx).Info().Strs("denied", denied).Msg("Ignoring changes denied by READ_ONLY or DENY_WRITE")
+	}
+}
+
+// allowedTags returns the tags the scene will have without denied changes, and which kinds of tag changes were
+// denied. Adding or removing FAVORITE_TAG is allowed by the favorite permission, TRASH_TAG by the delete permission
+// and all other tag changes by the tags permission.
+func (cs *ChangeSet) allowedTags(cfg config.ApplicationConfig) ([]string, []string) {
+	var special []specialTag
+	if cfg.FavoriteTag != "" {
+		special = append(special, specialTag{name: cfg.FavoriteTag, kind: config.WriteFavorite})
+	}
+	if cfg.TrashTag != "" {
+		special = append(special, specialTag{name: cfg.TrashTag, kind: config.WriteDelete})
+	}
+	isSpecial := func(name string) bool {
+		return slices.ContainsFunc(special, func(t specialTag) bool {
+			return t.name == name
+		})
+	}
+	original := slices.Sorted(slices.Values(tagNames(cs.scene.StashTags)))
+	requested := slices.Sorted(slices.Values(cs.tags))
+
+	var denied []string
+	tags := slices.DeleteFunc(slices.Clone(requested), isSpecial)
+	if !cfg.CanWrite(config.WriteTags) {
+		if originalTags := slices.DeleteFunc(slices.Clone(original), isSpecial); !slices.Equal(tags, originalTags) {
+			tags = originalTags
+			denied = append(denied, config.WriteTags)
+		}
+	}
+	for _, t := range special {
+		from := requested
+		if !cfg.CanWrite(t.kind) && slices.Contains(original, t.name) != slices.Contains(requested, t.name) {
+			from = original
+			denied = append(denied, t.kind)
+		}
+		if slices.Contains(from, t.name) {
+			tags = append(tags, t.name)
+		}
+	}
+	return slices.Compact(slices.Sorted(slices.Values(tags))), denied
+}
+
+// specialTag is a tag whose changes are allowed by its own kind of change rather than the tags permission.
+type specialTag struct {
+	name string
+	kind string
+}
@@ -0,0 +1,228 @@
+package library
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"stash-vr/internal/audit"
+	"stash-vr/internal/stash/gql"
+	"stash-vr/internal/util"
+
+	"github.com/rs/zerolog/log"
+)
+
+var ErrNotRevertible = errors.New("change can't be reverted")
+
+var errChangedSince = fmt.Errorf("%w: changed again since", ErrNotRevertible)
+
+// Revert undoes the change of an audit log entry. The change is undone relative to the current state of the scene, so
+// that later changes are kept, e.g. only the tags added by the change are removed. A field changed again since can't be
+// reverted.
+func (libraryService *Service) Revert(ctx context.Context, entryId string) error {
+	libraryService.muRevert.Lock()
+	defer libraryService.muRevert.Unlock()
+
+	e, ok := libraryService.auditLog.Find(entryId)
+	if !ok {
+		return fmt.Errorf("audit log entry %s not found", entryId)
+	}
+	if by, reverted := libraryService.auditLog.RevertedBy(e.Id); reverted {
+		return fmt.Errorf("%w: already reverted by %s", ErrNotRevertible, by)
+	}
+	if !e.IsRevertible() {
+		return fmt.Errorf("%w: %s", ErrNotRevertible, e.Kind)
+	}
+
+	ctx = audit.WithReverts(ctx, e.Id)
+	if _, err := libraryService.GetScene(ctx, e.SceneId, true); err != nil {
+		return err
+	}
+	cs, err := libraryService.NewChangeSet(ctx, e.SceneId)
+	if err != nil {
+		return err
+	}
+	if err := cs.revert(e); err != nil {
+		return fmt.Errorf("revert %s: %w", e.Kind, err)
+	}
+	if err := cs.Apply(ctx); err != nil {
+		return err
+	}
+	if _, err := libraryService.GetScene(ctx, e.SceneId, true); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("Failed to refresh scene after revert")
+	}
+	log.Ctx(ctx).Info().Str("entryId", e.Id).Str("sceneId", e.SceneId).Str("kind", string(e.Kind)).Msg("Reverted change")
+	return nil
+}
+
+// RevertSession reverts the changes of a session that haven't been reverted yet, newest first.
+func (libraryService *Service) RevertSession(ctx context.Context, sessionId string) error {
+	entries := libraryService.auditLog.Session(sessionId)
+	if len(entries) == 0 {
+		return fmt.Errorf("audit log session %s not found", sessionId)
+	}
+	var errs []error
+	for _, e := range slices.Backward(entries) {
+		if _, reverted := libraryService.auditLog.RevertedBy(e.Id); reverted || e.Reverts != "" || !e.IsRevertible() {
+			continue
+		}
+		if err := libraryService.Revert(ctx, e.Id); err != nil {
+			errs = append(errs, fmt.Errorf("entry %s: %w", e.Id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (cs *ChangeSet) revert(e audit.Entry) error {
+	switch e.Kind {
+	case audit.KindRating:
+		var before, after *int
+		if err := errors.Join(unmarshal(e.Before, &before), unmarshal(e.After, &after)); err != nil {
+			return err
+		}
+		if !util.PtrEqual(after, cs.scene.SceneParts.Rating100) {
+			return errChangedSince
+		}
+		cs.rating100 = before
+		cs.ratingSet = true
+	case audit.KindOrganized:
+		var before, after bool
+		if err := errors.Join(unmarshal(e.Before, &before), unmarshal(e.After, &after)); err != nil {
+			return err
+		}
+		if after != cs.scene.SceneParts.Organized {
+			return errChangedSince
+		}
+		cs.SetOrganized(before)
+	case audit.KindTags:
+		var before, after []string
+		if err := errors.Join(unmarshal(e.Before, &before), unmarshal(e.After, &after)); err != nil {
+			return err
+		}
+		cs.SetTags(revertNames(cs.Tags(), before, after))
+	case audit.KindPerformers:
+		var before, after []string
+		if err := errors.Join(unmarshal(e.Before, &before), unmarshal(e.After, &after)); err != nil {
+			return err
+		}
+		cs.SetPerformers(revertNames(cs.Performers(), before, after))
+	case audit.KindStudio:
+		var before, after string
+		if err := errors.Join(unmarshal(e.Before, &before), unmarshal(e.After, &after)); err != nil {
+			return err
+		}
+		if after != studioName(cs.scene) {
+			return errChangedSince
+		}
+		cs.SetStudio(before)
+	case audit.KindGroups:
+		var before, after []SceneGroup
+		if err := errors.Join(unmarshal(e.Before, &before), unmarshal(e.After, &after)); err != nil {
+			return err
+		}
+		cs.SetGroups(revertGroups(cs.Groups(), before, after))
+	case audit.KindTitle, audit.KindDate, audit.KindCode, audit.KindDetails:
+		var before, after string
+		if err := errors.Join(unmarshal(e.Before, &before), unmarshal(e.After, &after)); err != nil {
+			return err
+		}
+		for f, kind := range fieldKinds {
+			if kind == e.Kind {
+				if after != cs.scene.Field(f) {
+					return errChangedSince
+				}
+				return cs.SetField(f, before)
+			}
+		}
+	case audit.KindMarkerCreate:
+		var after MarkerDto
+		if err := unmarshal(e.After, &after); err != nil {
+			return err
+		}
+		if !cs.hasMarker(after.MarkerId) {
+			return fmt.Errorf("marker %s no longer exists", after.MarkerId)
+		}
+		cs.destroyMarkers = append(cs.destroyMarkers, after.MarkerId)
+	case audit.KindMarkerUpdate:
+		var before, after MarkerDto
+		if err := errors.Join(unmarshal(e.Before, &before), unmarshal(e.After, &after)); err != nil {
+			return err
+		}
+		if !cs.hasMarker(before.MarkerId) {
+			return fmt.Errorf("marker %s no longer exists", before.MarkerId)
+		}
+		if !sameMarker(after, cs.existingMarker(before.MarkerId)) {
+			return errChangedSince
+		}
+		cs.updateMarkers = append(cs.updateMarkers, before)
+	case audit.KindMarkerDestroy:
+		var before MarkerDto
+		if err := unmarshal(e.Before, &before); err != nil {
+			return err
+		}
+		cs.createMarkers = append(cs.createMarkers, before)
+	case audit.KindOCount:
+		var before, after int
+		if err := errors.Join(unmarshal(e.Before, &before), unmarshal(e.After, &after)); err != nil {
+			return err
+		}
+		cs.oDelta = max(before-after, -util.Deref(cs.scene.SceneParts.O_counter))
+	case audit.KindPlayCount:
+		var before, after int
+		if err := errors.Join(unmarshal(e.Before, &before), unmarshal(e.After, &after)); err != nil {
+			return err
+		}
+		cs.playCountDelta = max(before-after, -util.Deref(cs.scene.SceneParts.Play_count))
+	default:
+		return ErrNotRevertible
+	}
+	return nil
+}
+
+// revertNames removes the names added from before to after from current and adds back the names removed.
+func revertNames(current []string, before []string, after []string) []string {
+	names := slices.DeleteFunc(current, func(name string) bool {
+		return slices.Contains(after, name) && !slices.Contains(before, name)
+	})
+	for _, name := range before {
+		if !slices.Contains(after, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// revertGroups removes the groups added from before to after from current and adds back the groups removed or changed,
+// with their scene index from before.
+func revertGroups(current []SceneGroup, before []SceneGroup, after []SceneGroup) []SceneGroup {
+	hasName := func(groups []SceneGroup, name string) bool {
+		return slices.ContainsFunc(groups, func(g SceneGroup) bool {
+			return g.Name == name
+		})
+	}
+	groups := slices.DeleteFunc(current, func(g SceneGroup) bool {
+		return hasName(after, g.Name) && !hasName(before, g.Name)
+	})
+	for _, g := range before {
+		if !slices.ContainsFunc(after, func(other SceneGroup) bool {
+			return other.Name == g.Name && util.PtrEqual(other.SceneIndex, g.SceneIndex)
+		}) {
+			groups = append(groups, g)
+		}
+	}
+	return groups
+}
+
+func (cs *ChangeSet) hasMarker(markerId string) bool {
+	return slices.ContainsFunc(cs.scene.SceneParts.Scene_markers, func(sm *gql.ScenePartsScene_markersSceneMarker) bool {
+		return sm.Id == markerId
+	})
+}
+
+func unmarshal(data json.RawMessage, v any) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/rs/zerolog/log"
 	"stash-vr/internal/stash/gql"
+	"stash-vr/internal/util"
 	"strconv"
 	"time"
 )
@@ -41,6 +42,10 @@ func (libraryService *Service) GetScenes(ctx context.Context) (map[string]*Video
 		return libraryService.snapshot(), nil
 	})
 	if err != nil {
+		if cached := libraryService.fetchedSnapshot(); len(cached) > 0 {
+			log.Ctx(ctx).Warn().Err(err).Int("cached", len(cached)).Msg("Failed to fetch scenes, serving cached scenes")
+			return cached, nil
+		}
 		return nil, err
 	}
 	return res.(map[string]*VideoData), nil
@@ -61,6 +66,9 @@ func (libraryService *Service) GetScene(ctx context.Context, id string, forceFet
 	if err != nil {
 		return nil, err
 	}
+	if len(vds) == 0 {
+		return nil, fmt.Errorf("scene %s not found", id)
+	}
 
 	libraryService.muVdCache.Lock()
 	libraryService.vdCache[id] = vds[0]
@@ -69,16 +77,58 @@ func (libraryService *Service) GetScene(ctx context.Context, id string, forceFet
 	return vds[0], nil
 }
 
+// updateCached replaces the cached scene with a copy changed by update, so that the scene isn't refetched for changes
+// known to have been written. Readers of the previous scene are unaffected. Scenes not cached are left alone.
+func (libraryService *Service) updateCached(id string, update func(vd *VideoData)) {
+	libraryService.muVdCache.Lock()
+	defer libraryService.muVdCache.Unlock()
+	vd := libraryService.vdCache[id]
+	if vd == nil {
+		return
+	}
+	updated := *vd
+	sceneParts := *vd.SceneParts
+	updated.SceneParts = &sceneParts
+	update(&updated)
+	libraryService.vdCache[id] = &updated
+}
+
+// fetchVideoData fetches scenes together with their groups, or movies for older Stash versions.
 func (libraryService *Service) fetchVideoData(ctx context.Context, sceneIds []int) ([]*VideoData, error) {
-	resp, err := gql.FindScenes(ctx, libraryService.StashClient, sceneIds)
+	if !libraryService.Capabilities(ctx).SceneGroups {
+		resp, err := gql.FindScenesWithMovies(ctx, libraryService.StashClient, sceneIds)
+		if err != nil {
+			return nil, fmt.Errorf("FindScenesWithMovies: %w", err)
+		}
+		vds := make([]*VideoData, len(resp.FindScenes.Scenes))
+		for i, s := range resp.FindScenes.Scenes {
+			vds[i] = libraryService.newVideoData(&s.SceneParts)
+			for _, m := range s.Movies {
+				vds[i].Groups = append(vds[i].Groups, SceneGroup{Id: m.Movie.Id, Name: m.Movie.Name, SceneIndex: m.Scene_index})
+			}
+		}
+		return vds, nil
+	}
+
+	resp, err := gql.FindScenesWithGroups(ctx, libraryService.StashClient, sceneIds)
 	if err != nil {
-		return nil, fmt.Errorf("FindScenes: %w", err)
+		return nil, fmt.Errorf("FindScenesWithGroups: %w", err)
 	}
 	vds := make([]*VideoData, len(resp.FindScenes.Scenes))
 	for i, s := range resp.FindScenes.Scenes {
-		vd := VideoData{SceneParts: &s.SceneParts}
-		libraryService.decorateTags(&vd)
-		vds[i] = &vd
+		vds[i] = libraryService.newVideoData(&s.SceneParts)
+		for _, g := range s.Groups {
+			vds[i].Groups = append(vds[i].Groups, SceneGroup{Id: g.Group.Id, Name: g.Group.Name, SceneIndex: g.Scene_index})
+		}
 	}
 	return vds, nil
 }
+
+func (libraryService *Service) newVideoData(sp *gql.SceneParts) *VideoData {
+	vd := VideoData{SceneParts: sp}
+	for _, t := range sp.Tags {
+		vd.StashTags = append(vd.StashTags, Tag{Id: t.Id, Name: t.Name, SortName: util.FirstNonEmpty(&t.Sort_name, &t.Name)})
+	}
+	libraryService.decorateTags(&vd)
+	return &vd
+}
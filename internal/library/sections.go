@@ -34,6 +34,11 @@ func (libraryService *Service) GetSections(ctx context.Context) ([]Section, erro
 		if err != nil {
 			return nil, err
 		}
+		if trashedIds, err := libraryService.trashedIds(ctx); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("Failed to find scenes in trash, not hiding them")
+		} else {
+			sections = separateTrash(sections, trashedIds)
+		}
 
 		libraryService.muVdCache.Lock()
 		for k := range libraryService.vdCache {
@@ -48,6 +53,7 @@ func (libraryService *Service) GetSections(ctx context.Context) ([]Section, erro
 			}
 		}
 		libraryService.Stats.Scenes = len(libraryService.vdCache)
+		libraryService.sections = sections
 		libraryService.muVdCache.Unlock()
 
 		log.Ctx(ctx).Info().Int("sections", len(sections)).Int("links", libraryService.Stats.Links).
@@ -56,16 +62,34 @@ func (libraryService *Service) GetSections(ctx context.Context) ([]Section, erro
 
 		_ = libraryService.LoadTags(ctx)
 
-		log.Ctx(ctx).Debug().Int("tags", len(libraryService.tagCache)).Msg("Cached tags")
+		log.Ctx(ctx).Debug().Int("tags", len(libraryService.tags())).Msg("Cached tags")
 
 		return sections, nil
 	})
 	if err != nil {
+		libraryService.muVdCache.RLock()
+		cached := libraryService.sections
+		libraryService.muVdCache.RUnlock()
+		if cached != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("Failed to build index, serving cached sections")
+			return cached, nil
+		}
 		return nil, err
 	}
 	return res.([]Section), nil
 }
 
+// GetCachedSections returns the sections from when the index was last built, building it if it hasn't been yet.
+func (libraryService *Service) GetCachedSections(ctx context.Context) ([]Section, error) {
+	libraryService.muVdCache.RLock()
+	sections := libraryService.sections
+	libraryService.muVdCache.RUnlock()
+	if sections != nil {
+		return sections, nil
+	}
+	return libraryService.GetSections(ctx)
+}
+
 func (libraryService *Service) getDefaultSections(ctx context.Context) ([]Section, error) {
 	resp, err := gql.FindAllSceneIds(ctx, libraryService.StashClient)
 	if err != nil {
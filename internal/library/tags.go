@@ -21,7 +21,7 @@ func (libraryService *Service) LoadTags(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	libraryService.tagCache = make(map[string]*Tag)
+	tags := make(map[string]*Tag)
 	for _, st := range resp.FindTags.Tags {
 		t := Tag{
 			Id:       st.Id,
@@ -32,8 +32,11 @@ func (libraryService *Service) LoadTags(ctx context.Context) error {
 		for _, p := range st.Parents {
 			t.ParentIds = append(t.ParentIds, p.Id)
 		}
-		libraryService.tagCache[st.Id] = &t
+		tags[st.Id] = &t
 	}
+	libraryService.muTagCache.Lock()
+	libraryService.tagCache = tags
+	libraryService.muTagCache.Unlock()
 	return nil
 }
 
@@ -41,12 +44,13 @@ func (libraryService *Service) ancestors(tagId string) []Tag {
 	visited := map[string]struct{}{tagId: {}}
 	queue := []string{tagId}
 	out := []Tag{}
+	tags := libraryService.tags()
 
 	for len(queue) > 0 {
 		id := queue[0]
 		queue = queue[1:]
 
-		t := libraryService.tagCache[id]
+		t := tags[id]
 		if t == nil {
 			continue
 		}
@@ -55,7 +59,7 @@ func (libraryService *Service) ancestors(tagId string) []Tag {
 				continue
 			}
 			visited[pid] = struct{}{}
-			p := libraryService.tagCache[pid]
+			p := tags[pid]
 			queue = append(queue, pid)
 
 			out = append(out, *p)
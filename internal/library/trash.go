@@ -0,0 +1,211 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"stash-vr/internal/config"
+	"stash-vr/internal/stash/gql"
+	"stash-vr/internal/util"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	trashSectionName = "Trash"
+	// purgeInterval is how often scenes in the trash are checked for being due to be purged.
+	purgeInterval = time.Hour
+)
+
+var ErrNotTrashed = errors.New("scene is not in the trash")
+
+// TrashedScene is a scene in the trash.
+type TrashedScene struct {
+	Id        string
+	Title     string
+	TrashedAt time.Time
+	PurgeAt   time.Time
+}
+
+// IsTrashed reports whether the scene is tagged with TRASH_TAG.
+func (vd VideoData) IsTrashed() bool {
+	trashTag := config.Application().TrashTag
+	return trashTag != "" && slices.ContainsFunc(vd.StashTags, func(t Tag) bool {
+		return t.Name == trashTag
+	})
+}
+
+func (libraryService *Service) moveToTrash(ctx context.Context, id string) error {
+	if err := libraryService.setTrashed(ctx, id, true); err != nil {
+		return err
+	}
+	trashedAt := libraryService.trashStore.Add(ctx, id)
+	log.Ctx(ctx).Info().Str("videoId", id).Time("purgeAt", trashedAt.Add(config.Application().TrashRetention)).Msg("Moved scene to trash")
+	return nil
+}
+
+// Restore moves a scene out of the trash.
+func (libraryService *Service) Restore(ctx context.Context, id string) error {
+	if !config.Application().CanWrite(config.WriteDelete) {
+		return ErrWriteDenied
+	}
+	if err := libraryService.setTrashed(ctx, id, false); err != nil {
+		return err
+	}
+	libraryService.trashStore.Remove(ctx, id)
+	log.Ctx(ctx).Info().Str("videoId", id).Msg("Restored scene from trash")
+	return nil
+}
+
+// PurgeTrashed deletes a scene moved to the trash by Stash-VR together with its files. Scenes not in the trash are
+// refused with ErrNotTrashed.
+func (libraryService *Service) PurgeTrashed(ctx context.Context, id string) error {
+	if !config.Application().CanWrite(config.WriteDelete) {
+		return ErrWriteDenied
+	}
+	if _, ok := libraryService.trashStore.Trashed()[id]; !ok {
+		return ErrNotTrashed
+	}
+	vd, err := libraryService.GetScene(ctx, id, true)
+	if err != nil {
+		return err
+	}
+	if !vd.IsTrashed() {
+		return ErrNotTrashed
+	}
+	return libraryService.Purge(ctx, id)
+}
+
+func (libraryService *Service) setTrashed(ctx context.Context, id string, trashed bool) error {
+	trashTag := config.Application().TrashTag
+	if trashTag == "" {
+		return fmt.Errorf("TRASH_TAG is not set")
+	}
+	if _, err := libraryService.GetScene(ctx, id, true); err != nil {
+		return err
+	}
+	cs, err := libraryService.NewChangeSet(ctx, id)
+	if err != nil {
+		return err
+	}
+	tags := slices.DeleteFunc(cs.Tags(), func(name string) bool {
+		return name == trashTag
+	})
+	if trashed {
+		tags = append(tags, trashTag)
+	}
+	cs.SetTags(tags)
+	if err := cs.Apply(ctx); err != nil {
+		return err
+	}
+	if _, err := libraryService.GetScene(ctx, id, true); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("Failed to refresh scene")
+	}
+	return nil
+}
+
+// trashedIds returns the ids of the scenes tagged with TRASH_TAG in Stash.
+func (libraryService *Service) trashedIds(ctx context.Context) ([]string, error) {
+	trashTag := config.Application().TrashTag
+	if trashTag == "" {
+		return nil, nil
+	}
+	tagResp, err := gql.FindTagByName(ctx, libraryService.StashClient, trashTag)
+	if err != nil {
+		return nil, fmt.Errorf("FindTagByName (%s): %w", trashTag, err)
+	}
+	if len(tagResp.FindTags.Tags) == 0 {
+		return nil, nil
+	}
+	sceneFilter := gql.SceneFilterType{Tags: &gql.HierarchicalMultiCriterionInput{
+		Modifier: gql.CriterionModifierIncludes,
+		Value:    []string{tagResp.FindTags.Tags[0].Id},
+	}}
+	resp, err := gql.FindSceneIdsByFilter(ctx, libraryService.StashClient, &sceneFilter, &gql.FindFilterType{Per_page: util.Ptr(-1)})
+	if err != nil {
+		return nil, fmt.Errorf("FindSceneIdsByFilter: %w", err)
+	}
+	ids := make([]string, len(resp.FindScenes.Scenes))
+	for i, s := range resp.FindScenes.Scenes {
+		ids[i] = s.Id
+	}
+	return ids, nil
+}
+
+// separateTrash removes scenes in the trash from sections and adds a section with them.
+func separateTrash(sections []Section, trashedIds []string) []Section {
+	if len(trashedIds) == 0 {
+		return sections
+	}
+	for i := range sections {
+		sections[i].Ids = slices.DeleteFunc(slices.Clone(sections[i].Ids), func(id string) bool {
+			return slices.Contains(trashedIds, id)
+		})
+	}
+	sections = slices.DeleteFunc(sections, func(s Section) bool {
+		return len(s.Ids) == 0
+	})
+	return append(sections, Section{Name: trashSectionName, Ids: trashedIds})
+}
+
+// Trashed returns the scenes in the trash, oldest first.
+func (libraryService *Service) Trashed(ctx context.Context) []TrashedScene {
+	var out []TrashedScene
+	for id, trashedAt := range libraryService.trashStore.Trashed() {
+		s := TrashedScene{Id: id, TrashedAt: trashedAt, PurgeAt: trashedAt.Add(config.Application().TrashRetention)}
+		if vd, err := libraryService.GetScene(ctx, id, false); err == nil {
+			if !vd.IsTrashed() {
+				continue
+			}
+			s.Title = vd.Title()
+		}
+		out = append(out, s)
+	}
+	slices.SortFunc(out, func(a, b TrashedScene) int {
+		return a.TrashedAt.Compare(b.TrashedAt)
+	})
+	return out
+}
+
+// RunPurge purges scenes that have been in the trash for longer than TRASH_RETENTION until ctx is done.
+func (libraryService *Service) RunPurge(ctx context.Context) {
+	if config.Application().TrashTag == "" {
+		return
+	}
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+	for {
+		libraryService.purgeExpired(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeExpired purges the scenes moved to the trash by Stash-VR once expired. Scenes no longer tagged with TRASH_TAG
+// in Stash are forgotten, scenes tagged in Stash directly are never purged.
+func (libraryService *Service) purgeExpired(ctx context.Context) {
+	ids, err := libraryService.trashedIds(ctx)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("Failed to find scenes in trash")
+		return
+	}
+	for id, trashedAt := range libraryService.trashStore.Trashed() {
+		if !slices.Contains(ids, id) {
+			libraryService.trashStore.Remove(ctx, id)
+			continue
+		}
+		if time.Since(trashedAt) < config.Application().TrashRetention {
+			continue
+		}
+		if err := libraryService.Purge(ctx, id); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("videoId", id).Msg("Failed to purge scene from trash")
+			continue
+		}
+		log.Ctx(ctx).Info().Str("videoId", id).Msg("Purged scene from trash")
+	}
+}
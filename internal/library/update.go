@@ -4,84 +4,15 @@ import (
 	"context"
 	"fmt"
 	"github.com/rs/zerolog/log"
+	"math"
 	"slices"
+	"stash-vr/internal/audit"
 	"stash-vr/internal/config"
 	"stash-vr/internal/stash"
 	"stash-vr/internal/stash/gql"
-	"stash-vr/internal/util"
 	"time"
 )
 
-func (libraryService *Service) UpdateRating(ctx context.Context, id string, rating5 *float32) error {
-	var newRating100 *int
-	if rating5 != nil {
-		converted := int(*rating5 * 20)
-		newRating100 = &converted
-	}
-
-	_, err := gql.SceneUpdateRating100(ctx, libraryService.StashClient, id, newRating100)
-	if err != nil {
-		return fmt.Errorf("SceneUpdateRating100: %w", err)
-	}
-	return nil
-}
-
-func (libraryService *Service) UpdateFavorite(ctx context.Context, id string, isFavoriteRequested bool) error {
-	favoriteTagName := config.Application().FavoriteTag
-
-	if favoriteTagName == "" {
-		log.Ctx(ctx).Info().Msg("Sync favorite requested but FAVORITE_TAG is empty, ignoring request")
-		return nil
-	}
-
-	favoriteTagId, err := stash.FindOrCreateTag(ctx, libraryService.StashClient, favoriteTagName)
-	if err != nil {
-		return err
-	}
-
-	response, err := gql.FindSceneTags(ctx, libraryService.StashClient, id)
-	if err != nil {
-		return fmt.Errorf("FindSceneTags: %w", err)
-	}
-
-	newTagIds := make([]string, 0, len(response.FindScene.Tags)+1)
-
-	var hasFavoriteTag bool
-	for _, t := range response.FindScene.Tags {
-		if t.Id == favoriteTagId {
-			hasFavoriteTag = true
-			if !isFavoriteRequested {
-				continue
-			}
-		}
-		newTagIds = append(newTagIds, t.Id)
-	}
-	if !hasFavoriteTag && isFavoriteRequested {
-		newTagIds = append(newTagIds, favoriteTagId)
-	}
-
-	if _, err := gql.SceneUpdateTags(ctx, libraryService.StashClient, id, newTagIds); err != nil {
-		return fmt.Errorf("SceneUpdateTags: %w", err)
-	}
-
-	return nil
-}
-
-func (libraryService *Service) UpdateTags(ctx context.Context, id string, tags []string) error {
-	tagIds := make([]string, len(tags))
-	for i, tag := range tags {
-		tagId, err := stash.FindOrCreateTag(ctx, libraryService.StashClient, tag)
-		if err != nil {
-			return err
-		}
-		tagIds[i] = tagId
-	}
-	if _, err := gql.SceneUpdateTags(ctx, libraryService.StashClient, id, tagIds); err != nil {
-		return fmt.Errorf("SceneUpdateTags: %w", err)
-	}
-	return nil
-}
-
 type MarkerDto struct {
 	PrimaryTagName string
 	StartSecond    float64
@@ -90,156 +21,153 @@ type MarkerDto struct {
 	MarkerId       string //hack: use the rating field for transport of marker id
 }
 
-func (libraryService *Service) UpdateMarkers(ctx context.Context, id string, incomingMarkers []MarkerDto) error {
-	vd, err := libraryService.GetScene(ctx, id, false)
-	if err != nil {
-		return err
+// MarkerOf returns the marker as exchanged with clients.
+func MarkerOf(sm *gql.ScenePartsScene_markersSceneMarker) MarkerDto {
+	return MarkerDto{
+		PrimaryTagName: sm.Primary_tag.Name,
+		StartSecond:    sm.Seconds,
+		EndSecond:      sm.End_seconds,
+		Title:          sm.Title,
+		MarkerId:       sm.Id,
 	}
+}
 
-	markersToDestroy := make([]string, 0)
-	for _, existingMarker := range vd.SceneParts.Scene_markers {
-		if !slices.ContainsFunc(incomingMarkers, func(m MarkerDto) bool {
-			return m.MarkerId == existingMarker.Id
-		}) {
-			markersToDestroy = append(markersToDestroy, existingMarker.Id)
-		}
-	}
-
-	markersToUpdate := make([]MarkerDto, 0)
-	markersToCreate := make([]MarkerDto, 0)
-
-	for _, incoming := range incomingMarkers {
-		if incoming.MarkerId != "" && incoming.MarkerId != "0" && slices.ContainsFunc(vd.SceneParts.Scene_markers, func(existingMarker *gql.ScenePartsScene_markersSceneMarker) bool {
-			return incoming.MarkerId == existingMarker.Id
-		}) {
-			markersToUpdate = append(markersToUpdate, incoming)
-		} else {
-			markersToCreate = append(markersToCreate, incoming)
+func sameMarker(a MarkerDto, b MarkerDto) bool {
+	const epsilon = 0.001
+	sameSecond := func(x, y *float64) bool {
+		if x == nil || y == nil {
+			return x == y
 		}
+		return math.Abs(*x-*y) < epsilon
 	}
+	return a.PrimaryTagName == b.PrimaryTagName && a.Title == b.Title &&
+		sameSecond(&a.StartSecond, &b.StartSecond) && sameSecond(a.EndSecond, b.EndSecond)
+}
 
-	for _, m := range markersToUpdate {
-		tagId, err := stash.FindOrCreateTag(ctx, libraryService.StashClient, m.PrimaryTagName)
-		if err != nil {
-			return fmt.Errorf("failed to find or create primary tag for marker: %w", err)
-		}
-		_, err = gql.SceneMarkerUpdate(ctx, libraryService.StashClient, m.MarkerId, tagId, m.StartSecond, m.EndSecond, m.Title)
-		if err != nil {
-			return fmt.Errorf("SceneMarkerCreate: %w", err)
-		}
+// Delete moves a scene to the trash if TRASH_TAG is set. Otherwise, or if the scene already is in the trash, it's
+// deleted together with its files.
+func (libraryService *Service) Delete(ctx context.Context, id string) error {
+	if !config.Application().CanWrite(config.WriteDelete) {
+		return ErrWriteDenied
 	}
-	for _, m := range markersToCreate {
-		tagId, err := stash.FindOrCreateTag(ctx, libraryService.StashClient, m.PrimaryTagName)
+	if config.Application().TrashTag != "" {
+		vd, err := libraryService.GetScene(ctx, id, true)
 		if err != nil {
-			return fmt.Errorf("failed to find or create primary tag for marker: %w", err)
+			return err
 		}
-		_, err = gql.SceneMarkerCreate(ctx, libraryService.StashClient, id, tagId, m.StartSecond, m.EndSecond, m.Title)
-		if err != nil {
-			return fmt.Errorf("SceneMarkerCreate: %w", err)
+		if !vd.IsTrashed() {
+			return libraryService.moveToTrash(ctx, id)
 		}
 	}
-
-	_, err = gql.SceneMarkersDestroy(ctx, libraryService.StashClient, markersToDestroy)
-	if err != nil {
-		return fmt.Errorf("SceneMarkersDestroy: %w", err)
-	}
-
-	return nil
+	return libraryService.Purge(ctx, id)
 }
 
-func (libraryService *Service) ClearAndCreateMarkers(ctx context.Context, id string, markers []MarkerDto) error {
-	resp, err := gql.FindSceneMarkers(ctx, libraryService.StashClient, id)
-	if err != nil {
-		return fmt.Errorf("FindSceneMarkers: %w", err)
-	}
-	currentMarkers := make([]MarkerDto, len(resp.FindSceneMarkers.Scene_markers))
-	for i, m := range resp.FindSceneMarkers.Scene_markers {
-		currentMarkers[i] = MarkerDto{
-			PrimaryTagName: m.Primary_tag.Name,
-			StartSecond:    m.Seconds * 1000,
-			Title:          m.Title,
-		}
-		if m.End_seconds != nil {
-			currentMarkers[i].EndSecond = util.Ptr(*m.End_seconds * 1000)
-		}
-	}
-	if util.UnorderedEqual(currentMarkers, markers) {
-		return nil
-	}
-	markersToDestroy := make([]string, len(resp.FindSceneMarkers.Scene_markers))
-	for i, sm := range resp.FindSceneMarkers.Scene_markers {
-		markersToDestroy[i] = sm.Id
-	}
-	_, err = gql.SceneMarkersDestroy(ctx, libraryService.StashClient, markersToDestroy)
-	if err != nil {
-		return fmt.Errorf("SceneMarkersDestroy: %w", err)
+// Purge deletes a scene together with its files.
+func (libraryService *Service) Purge(ctx context.Context, id string) error {
+	if !config.Application().CanWrite(config.WriteDelete) {
+		return ErrWriteDenied
 	}
-
-	for _, m := range markers {
-		tagId, err := stash.FindOrCreateTag(ctx, libraryService.StashClient, m.PrimaryTagName)
-		if err != nil {
-			return fmt.Errorf("failed to find or create primary tag for marker: %w", err)
-		}
-		_, err = gql.SceneMarkerCreate(ctx, libraryService.StashClient, id, tagId, m.StartSecond, m.EndSecond, m.Title)
-		if err != nil {
-			return fmt.Errorf("SceneMarkerCreate: %w", err)
-		}
+	var title string
+	if vd, err := libraryService.GetScene(ctx, id, false); err == nil {
+		title = vd.Title()
 	}
-	return nil
-}
-
-func (libraryService *Service) Delete(ctx context.Context, id string) error {
 	if _, err := gql.SceneDestroy(ctx, libraryService.StashClient, id); err != nil {
 		return fmt.Errorf("SceneDestroy: %w", err)
 	}
+	libraryService.auditLog.Record(ctx, id, audit.KindDelete, title, nil)
+	libraryService.trashStore.Remove(ctx, id)
 	return nil
 }
 
-func (libraryService *Service) IncrementO(ctx context.Context, id string) error {
-	_, err := gql.SceneIncrementO(ctx, libraryService.StashClient, id)
+func (libraryService *Service) IncrementPlayCount(ctx context.Context, id string) error {
+	cs, err := libraryService.NewChangeSet(ctx, id)
 	if err != nil {
-		return fmt.Errorf("SceneIncrementO: %w", err)
+		return err
 	}
-	return nil
+	cs.IncrementPlayCount()
+	return cs.Apply(ctx)
 }
 
-func (libraryService *Service) DecrementO(ctx context.Context, id string) error {
-	_, err := gql.SceneDecrementO(ctx, libraryService.StashClient, id)
+func (libraryService *Service) AddPlayDuration(ctx context.Context, id string, duration time.Duration) error {
+	if !config.Application().CanWrite(config.WritePlay) {
+		log.Ctx(ctx).Trace().Err(ErrWriteDenied).Msg("Skipping play duration")
+		return nil
+	}
+	if !libraryService.Capabilities(ctx).SaveActivity {
+		log.Ctx(ctx).Trace().Err(stash.ErrUnsupported).Msg("Skipping play duration")
+		return nil
+	}
+	seconds := duration.Seconds()
+	_, err := gql.SceneAddPlayDurationSeconds(ctx, libraryService.StashClient, id, &seconds)
 	if err != nil {
-		return fmt.Errorf("SceneDecrementO: %w", err)
+		return fmt.Errorf("SceneAddPlayDurationSeconds: %w", err)
 	}
 	return nil
 }
 
-func (libraryService *Service) IncrementPlayCount(ctx context.Context, id string) error {
-	_, err := gql.SceneIncrementPlayCount(ctx, libraryService.StashClient, id)
+func (libraryService *Service) SaveResumeTime(ctx context.Context, id string, seconds float64) error {
+	if !config.Application().CanWrite(config.WritePlay) {
+		log.Ctx(ctx).Trace().Err(ErrWriteDenied).Msg("Skipping resume time")
+		return nil
+	}
+	if !libraryService.Capabilities(ctx).SaveActivity {
+		log.Ctx(ctx).Trace().Err(stash.ErrUnsupported).Msg("Skipping resume time")
+		return nil
+	}
+	_, err := gql.SceneSaveResumeTime(ctx, libraryService.StashClient, id, &seconds)
 	if err != nil {
-		return fmt.Errorf("SceneIncrementPlayCount: %w", err)
+		return fmt.Errorf("SceneSaveResumeTime: %w", err)
 	}
+	libraryService.updateCached(id, func(vd *VideoData) {
+		vd.SceneParts.Resume_time = &seconds
+	})
 	return nil
 }
 
-func (libraryService *Service) DecrementPlayCount(ctx context.Context, id string) error {
-	_, err := gql.SceneDecrementPlayCount(ctx, libraryService.StashClient, id)
+// ReplaceMarkers destroys the markers of a scene with ids in previous that still have primary tag tagName and creates
+// markers with that primary tag. It returns the ids of the markers created, also when failing part way.
+func (libraryService *Service) ReplaceMarkers(ctx context.Context, id string, previous []string, tagName string, markers []MarkerDto) ([]string, error) {
+	if !config.Application().CanWrite(config.WriteMarkers) {
+		log.Ctx(ctx).Trace().Err(ErrWriteDenied).Msg("Skipping markers")
+		return previous, nil
+	}
+	tagId, err := stash.FindOrCreateTag(ctx, libraryService.StashClient, tagName)
 	if err != nil {
-		return fmt.Errorf("SceneDecrementPlayCount: %w", err)
+		return previous, fmt.Errorf("failed to find or create primary tag for marker: %w", err)
 	}
-	return nil
-}
 
-func (libraryService *Service) SetOrganized(ctx context.Context, id string, newState bool) error {
-	_, err := gql.SceneUpdateOrganized(ctx, libraryService.StashClient, id, &newState)
-	if err != nil {
-		return fmt.Errorf("SceneUpdateOrganized: %w", err)
+	if len(previous) > 0 {
+		resp, err := gql.FindSceneMarkers(ctx, libraryService.StashClient, id)
+		if err != nil {
+			return previous, fmt.Errorf("FindSceneMarkers: %w", err)
+		}
+		markersToDestroy := make([]string, 0)
+		var destroyed []MarkerDto
+		for _, m := range resp.FindSceneMarkers.Scene_markers {
+			if m.Primary_tag.Id == tagId && slices.Contains(previous, m.Id) {
+				markersToDestroy = append(markersToDestroy, m.Id)
+				destroyed = append(destroyed, MarkerOf(&gql.ScenePartsScene_markersSceneMarker{SceneMarkerParts: m.SceneMarkerParts}))
+			}
+		}
+		if len(markersToDestroy) > 0 {
+			if _, err := gql.SceneMarkersDestroy(ctx, libraryService.StashClient, markersToDestroy); err != nil {
+				return previous, fmt.Errorf("SceneMarkersDestroy: %w", err)
+			}
+		}
+		for _, m := range destroyed {
+			libraryService.auditLog.Record(ctx, id, audit.KindMarkerDestroy, m, nil)
+		}
 	}
-	return nil
-}
 
-func (libraryService *Service) AddPlayDuration(ctx context.Context, id string, duration time.Duration) error {
-	seconds := duration.Seconds()
-	_, err := gql.SceneAddPlayDurationSeconds(ctx, libraryService.StashClient, id, &seconds)
-	if err != nil {
-		return fmt.Errorf("SceneAddPlayDurationSeconds: %w", err)
+	created := make([]string, 0, len(markers))
+	for _, m := range markers {
+		resp, err := gql.SceneMarkerCreate(ctx, libraryService.StashClient, id, tagId, m.StartSecond, m.EndSecond, m.Title)
+		if err != nil {
+			return created, fmt.Errorf("SceneMarkerCreate: %w", err)
+		}
+		created = append(created, resp.SceneMarkerCreate.Id)
+		m.MarkerId = resp.SceneMarkerCreate.Id
+		libraryService.auditLog.Record(ctx, id, audit.KindMarkerCreate, nil, m)
 	}
-	return nil
+	return created, nil
 }
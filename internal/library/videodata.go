@@ -7,6 +7,18 @@ import (
 
 type VideoData struct {
 	SceneParts *gql.SceneParts
+	// Groups are the groups, or movies for older Stash versions, of the scene.
+	Groups []SceneGroup
+	// StashTags are the tags of the scene in Stash. Tags in SceneParts are decorated for display, without excluded tags
+	// and with ancestors added.
+	StashTags []Tag
+}
+
+// SceneGroup is a group, or movie for older Stash versions, a scene belongs to.
+type SceneGroup struct {
+	Id         string `json:"id"`
+	Name       string `json:"name"`
+	SceneIndex *int   `json:"sceneIndex,omitempty"`
 }
 
 func (vd VideoData) Title() string {
@@ -0,0 +1,123 @@
+package playback
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"stash-vr/internal/config"
+	"stash-vr/internal/history"
+	"stash-vr/internal/library"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	replayMarkerFile = "replay_markers.json"
+	// replayMinDistance is the minimum number of buckets between two replay markers.
+	replayMinDistance = 10
+	// replayMinCount is how many times a part must have been watched to be considered replayed.
+	replayMinCount = 2
+)
+
+// replayMarkers holds the ids of the replay markers created in each scene, so that only those are replaced and markers
+// made by hand with the same tag are kept. Updates are made one at a time, outside of event handling. The ids are
+// saved to CONFIG_PATH, or kept in memory only if CONFIG_PATH is not specified.
+type replayMarkers struct {
+	mu         sync.Mutex
+	bySceneId  map[string][]string
+	updateWait sync.WaitGroup
+}
+
+func loadReplayMarkers(ctx context.Context) *replayMarkers {
+	r := &replayMarkers{bySceneId: make(map[string][]string)}
+	if config.Application().ConfigPath == "" {
+		return r
+	}
+	path := filepath.Join(config.Application().ConfigPath, replayMarkerFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to read replay marker file")
+		}
+		return r
+	}
+	if err := json.Unmarshal(data, &r.bySceneId); err != nil || r.bySceneId == nil {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to parse replay marker file")
+		r.bySceneId = make(map[string][]string)
+	}
+	return r
+}
+
+func (r *replayMarkers) save(ctx context.Context) {
+	if config.Application().ConfigPath == "" {
+		return
+	}
+	path := filepath.Join(config.Application().ConfigPath, replayMarkerFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("error creating config directory")
+		return
+	}
+	data, err := json.MarshalIndent(r.bySceneId, "", "  ")
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to encode replay markers")
+		return
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to save replay markers")
+	}
+}
+
+// updateReplayMarkers replaces the most replayed markers of a scene in the background, if enabled by
+// REPLAY_MARKER_TAG.
+func (t *Tracker) updateReplayMarkers(ctx context.Context, sceneId string) {
+	if config.Application().ReplayMarkerTag == "" || config.Application().ReplayMarkerCount <= 0 {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	t.replayMarkers.updateWait.Add(1)
+	go func() {
+		defer t.replayMarkers.updateWait.Done()
+		t.replaceReplayMarkers(ctx, sceneId)
+	}()
+}
+
+func (t *Tracker) replaceReplayMarkers(ctx context.Context, sceneId string) {
+	tagName := config.Application().ReplayMarkerTag
+	h, ok := t.history.Histogram(sceneId, history.ReplayBuckets)
+	if !ok {
+		return
+	}
+	peaks := h.Peaks(config.Application().ReplayMarkerCount, replayMinDistance, replayMinCount)
+	if len(peaks) == 0 {
+		return
+	}
+
+	markers := make([]library.MarkerDto, len(peaks))
+	for i, p := range peaks {
+		markers[i] = library.MarkerDto{
+			PrimaryTagName: tagName,
+			StartSecond:    h.BucketStart(p.Bucket),
+			Title:          fmt.Sprintf("Replayed %dx", p.Count),
+		}
+	}
+
+	r := t.replayMarkers
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	log.Ctx(ctx).Debug().Int("markers", len(markers)).Msg("Updating most replayed markers")
+	ids, err := t.libraryService.ReplaceMarkers(ctx, sceneId, r.bySceneId[sceneId], tagName, markers)
+	r.bySceneId[sceneId] = ids
+	r.save(ctx)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("Failed to update most replayed markers")
+		return
+	}
+	if _, err := t.libraryService.GetScene(ctx, sceneId, true); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("Failed to refetch scene")
+	}
+}
@@ -0,0 +1,134 @@
+package playback
+
+import (
+	"cmp"
+	"slices"
+	"stash-vr/internal/history"
+	"time"
+)
+
+// Event is a position reported by the player.
+type Event struct {
+	// Position in seconds.
+	Position float64
+	At       time.Time
+	Speed    float64
+	// Device is the name of the player shown in the watch history.
+	Device string
+	// Client identifies the player, each client has its own session.
+	Client string
+}
+
+type Interval struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+type Session struct {
+	Client        string  `json:"client"`
+	VideoId       string  `json:"videoId"`
+	VideoDuration float64 `json:"videoDuration"`
+
+	Watched          []Interval    `json:"watched"`
+	ThresholdReached bool          `json:"thresholdReached"`
+	Unreported       time.Duration `json:"unreported"`
+
+	IsPlaying    bool      `json:"isPlaying"`
+	SegmentStart float64   `json:"segmentStart"`
+	SegmentAt    time.Time `json:"segmentAt"`
+	SegmentSpeed float64   `json:"segmentSpeed"`
+
+	// LastPosition and LastAt are the position and time of the last event reported by the player.
+	LastPosition float64   `json:"lastPosition"`
+	LastAt       time.Time `json:"lastAt"`
+
+	// Viewing is the watch history record being built, nil between viewings.
+	Viewing *history.Record `json:"viewing,omitempty"`
+}
+
+// play starts a new segment at the position of ev. If a segment is already running, e.g. when the player
+// seeks without pausing, it is closed first using the elapsed time as the only estimate of how far it got.
+func (s *Session) play(ev Event) {
+	if s.IsPlaying {
+		s.closeSegment(nil, ev.At)
+	}
+	s.IsPlaying = true
+	s.SegmentStart = ev.Position
+	s.SegmentAt = ev.At
+	s.SegmentSpeed = ev.Speed
+	s.seen(ev)
+}
+
+// stop closes the running segment. ev is the event that stopped playback, nil if playback stopped without the player
+// reporting it, e.g. when it switched video or on shutdown. The segment is then closed now, at the position reached
+// playing on at the segment speed since it started, capped at the end of the video.
+func (s *Session) stop(ev *Event) {
+	if !s.IsPlaying {
+		return
+	}
+	if ev == nil {
+		now := time.Now()
+		position := min(s.SegmentStart+now.Sub(s.SegmentAt).Seconds()*s.SegmentSpeed, s.VideoDuration)
+		ev = &Event{Position: position, At: now}
+	}
+	s.closeSegment(ev, ev.At)
+	s.IsPlaying = false
+	s.seen(*ev)
+}
+
+func (s *Session) seen(ev Event) {
+	s.LastPosition = ev.Position
+	s.LastAt = ev.At
+}
+
+// closeSegment records the part of the timeline covered since the segment started along with the time spent
+// watching it. Coverage is bounded by both the player position and the elapsed time at the current speed, so seeking
+// forward or a headset going to sleep mid-segment doesn't count as watched.
+func (s *Session) closeSegment(ev *Event, at time.Time) {
+	elapsed := at.Sub(s.SegmentAt).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	covered := elapsed * s.SegmentSpeed
+	if ev != nil {
+		if progress := ev.Position - s.SegmentStart; progress >= 0 {
+			covered = min(covered, progress)
+		}
+	}
+	end := min(s.SegmentStart+covered, s.VideoDuration)
+	if end <= s.SegmentStart {
+		return
+	}
+	s.addWatched(Interval{Start: s.SegmentStart, End: end})
+	if s.Viewing != nil {
+		s.Viewing.Segments = append(s.Viewing.Segments, history.Segment{From: s.SegmentStart, To: end, Speed: s.SegmentSpeed})
+		s.Viewing.End = at
+	}
+	s.Unreported += time.Duration((end - s.SegmentStart) / s.SegmentSpeed * float64(time.Second))
+}
+
+func (s *Session) addWatched(iv Interval) {
+	s.Watched = append(s.Watched, iv)
+	slices.SortFunc(s.Watched, func(a, b Interval) int {
+		return cmp.Compare(a.Start, b.Start)
+	})
+
+	merged := s.Watched[:1]
+	for _, next := range s.Watched[1:] {
+		last := &merged[len(merged)-1]
+		if next.Start <= last.End {
+			last.End = max(last.End, next.End)
+			continue
+		}
+		merged = append(merged, next)
+	}
+	s.Watched = merged
+}
+
+func (s *Session) WatchedSeconds() float64 {
+	total := 0.0
+	for _, iv := range s.Watched {
+		total += iv.End - iv.Start
+	}
+	return total
+}
@@ -0,0 +1,84 @@
+package playback
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"stash-vr/internal/config"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	sessionFile = "playback.json"
+)
+
+// persist writes the sessions to CONFIG_PATH, removing the file when there are no sessions. Does nothing if
+// CONFIG_PATH is not specified.
+func (t *Tracker) persist(ctx context.Context) {
+	if config.Application().ConfigPath == "" {
+		return
+	}
+	path := resolvePath()
+
+	if len(t.sessions) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to remove playback session file")
+		}
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("error creating config directory")
+		return
+	}
+	data, err := json.MarshalIndent(t.sessions, "", "  ")
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to encode playback sessions")
+		return
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to save playback session")
+	}
+}
+
+// load reads the sessions persisted by a previous run. Segments left open by an unclean exit are discarded since
+// there's no telling how much of them was watched. A single session persisted by an older version is read too.
+func load(ctx context.Context) map[string]*Session {
+	sessions := make(map[string]*Session)
+	if config.Application().ConfigPath == "" {
+		return sessions
+	}
+	path := resolvePath()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to read playback session file")
+		}
+		return sessions
+	}
+
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		var s Session
+		if err := json.Unmarshal(data, &s); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to parse playback session file")
+			return sessions
+		}
+		sessions = map[string]*Session{s.Client: &s}
+	}
+	maps.DeleteFunc(sessions, func(_ string, s *Session) bool {
+		return s == nil || s.VideoId == ""
+	})
+	for _, s := range sessions {
+		s.IsPlaying = false
+	}
+	return sessions
+}
+
+func resolvePath() string {
+	return filepath.Join(config.Application().ConfigPath, sessionFile)
+}
@@ -0,0 +1,213 @@
+package playback
+
+import (
+	"context"
+	"stash-vr/internal/history"
+	"stash-vr/internal/library"
+	"stash-vr/internal/stash"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Tracker accumulates playback of the scene currently playing on each client and reports it to Stash as play duration
+// and play count.
+type Tracker struct {
+	libraryService *library.Service
+	history        *history.Store
+	replayMarkers  *replayMarkers
+
+	mu sync.Mutex
+	// sessions are the sessions of each client by Event.Client.
+	sessions        map[string]*Session
+	minPlayFraction *float64
+}
+
+// pending is what a session has to report to Stash and the watch history. It's collected while holding the lock and
+// reported after releasing it, so that a slow Stash doesn't hold up events of other clients.
+type pending struct {
+	session      *Session
+	playCount    bool
+	playDuration time.Duration
+	viewing      *history.Record
+}
+
+func NewTracker(ctx context.Context, libraryService *library.Service, historyStore *history.Store) *Tracker {
+	t := &Tracker{libraryService: libraryService, history: historyStore, replayMarkers: loadReplayMarkers(ctx), sessions: load(ctx)}
+	var work []pending
+	if len(t.sessions) > 0 {
+		// Report what was left unreported before a restart, e.g. when shutdown didn't wait for Stash.
+		mpf := t.getMinPlayFraction(ctx)
+		for _, s := range t.sessions {
+			log.Ctx(ctx).Debug().Str("videoId", s.VideoId).Str("client", s.Client).Float64("watched seconds", s.WatchedSeconds()).Msg("Restored playback session")
+			work = append(work, s.collect(mpf, true))
+		}
+	}
+	t.persist(ctx)
+	t.do(ctx, work)
+	return t
+}
+
+// SetMinPlayFraction sets the fraction of a scene that must be watched for it to count as played.
+func (t *Tracker) SetMinPlayFraction(f float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.minPlayFraction = &f
+}
+
+func (t *Tracker) Play(ctx context.Context, vd *library.VideoData, ev Event) {
+	mpf := t.getMinPlayFraction(ctx)
+	var work []pending
+
+	t.mu.Lock()
+	s := t.sessions[ev.Client]
+	if s != nil && s.VideoId != vd.Id() {
+		s.stop(nil)
+		work = append(work, s.collect(mpf, true))
+		s = nil
+	}
+	if s == nil {
+		s = &Session{
+			Client:        ev.Client,
+			VideoId:       vd.Id(),
+			VideoDuration: vd.SceneParts.Files[0].Duration,
+		}
+		t.sessions[ev.Client] = s
+	}
+	if s.Viewing == nil {
+		s.Viewing = newViewing(vd, ev)
+	}
+	s.play(ev)
+	t.persist(ctx)
+	t.mu.Unlock()
+
+	t.do(ctx, work)
+}
+
+func (t *Tracker) Stop(ctx context.Context, videoId string, ev Event) {
+	t.stop(ctx, videoId, ev, false)
+}
+
+// Close stops the session like Stop and also ends the current viewing in the watch history.
+func (t *Tracker) Close(ctx context.Context, videoId string, ev Event) {
+	t.stop(ctx, videoId, ev, true)
+}
+
+func (t *Tracker) stop(ctx context.Context, videoId string, ev Event, endViewing bool) {
+	mpf := t.getMinPlayFraction(ctx)
+
+	t.mu.Lock()
+	s := t.sessions[ev.Client]
+	if s == nil || s.VideoId != videoId {
+		t.mu.Unlock()
+		return
+	}
+	s.stop(&ev)
+	work := []pending{s.collect(mpf, endViewing)}
+	t.persist(ctx)
+	t.mu.Unlock()
+
+	t.do(ctx, work)
+}
+
+// Flush stops all sessions, counting running segments up to now, reports them to Stash and persists them so that
+// watched parts still count towards the play count after a restart. Sessions are persisted before reporting too, so
+// the time watched isn't lost if the process is killed before Stash responds. It waits for replay markers being
+// updated.
+func (t *Tracker) Flush(ctx context.Context) {
+	mpf := t.getMinPlayFraction(ctx)
+
+	t.mu.Lock()
+	var work []pending
+	for _, s := range t.sessions {
+		log.Ctx(ctx).Debug().Str("videoId", s.VideoId).Str("client", s.Client).Msg("Flushing playback session")
+		s.stop(nil)
+	}
+	t.persist(ctx)
+	for _, s := range t.sessions {
+		work = append(work, s.collect(mpf, true))
+	}
+	t.mu.Unlock()
+
+	t.do(ctx, work)
+
+	t.mu.Lock()
+	t.persist(ctx)
+	t.mu.Unlock()
+	t.replayMarkers.updateWait.Wait()
+}
+
+func (t *Tracker) getMinPlayFraction(ctx context.Context) float64 {
+	t.mu.Lock()
+	mpf := t.minPlayFraction
+	t.mu.Unlock()
+	if mpf != nil {
+		return *mpf
+	}
+	f := stash.GetMinPlayPercent(ctx, t.libraryService.StashClient) / 100
+	t.SetMinPlayFraction(f)
+	return f
+}
+
+// collect takes what s has to report, assuming it will be reported. endViewing also takes the current viewing.
+func (s *Session) collect(minPlayFraction float64, endViewing bool) pending {
+	p := pending{session: s}
+	if !s.ThresholdReached && s.WatchedSeconds() >= s.VideoDuration*minPlayFraction {
+		s.ThresholdReached = true
+		p.playCount = true
+	}
+	p.playDuration, s.Unreported = s.Unreported, 0
+	if endViewing {
+		p.viewing = s.takeViewing()
+	}
+	return p
+}
+
+// takeViewing removes the viewing of s, returning it if anything was watched.
+func (s *Session) takeViewing() *history.Record {
+	v := s.Viewing
+	s.Viewing = nil
+	if v == nil || len(v.Segments) == 0 {
+		return nil
+	}
+	return v
+}
+
+// do reports work to Stash and the watch history. What fails to be reported to Stash is given back to the session to
+// be reported with its next report.
+func (t *Tracker) do(ctx context.Context, work []pending) {
+	for _, p := range work {
+		s := p.session
+		ctx := log.Ctx(ctx).With().Str("videoId", s.VideoId).Logger().WithContext(ctx)
+		if p.playCount {
+			log.Ctx(ctx).Debug().Float64("watched seconds", s.WatchedSeconds()).Msg("Incrementing play count")
+			if err := t.libraryService.IncrementPlayCount(ctx, s.VideoId); err != nil {
+				log.Ctx(ctx).Warn().Err(err).Msg("Failed to increment play count")
+				t.giveBack(ctx, s, func() {
+					s.ThresholdReached = false
+				})
+			}
+		}
+		if p.playDuration > 0 {
+			log.Ctx(ctx).Debug().Str("duration", p.playDuration.Round(time.Second).String()).Msg("Adding play duration")
+			if err := t.libraryService.AddPlayDuration(ctx, s.VideoId, p.playDuration); err != nil {
+				log.Ctx(ctx).Warn().Err(err).Msg("Failed to add play duration")
+				t.giveBack(ctx, s, func() {
+					s.Unreported += p.playDuration
+				})
+			}
+		}
+		if p.viewing != nil {
+			t.history.Append(ctx, *p.viewing)
+			t.updateReplayMarkers(ctx, s.VideoId)
+		}
+	}
+}
+
+func (t *Tracker) giveBack(ctx context.Context, s *Session, f func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	f()
+	t.persist(ctx)
+}
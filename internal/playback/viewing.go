@@ -0,0 +1,33 @@
+package playback
+
+import (
+	"stash-vr/internal/config"
+	"stash-vr/internal/history"
+	"stash-vr/internal/library"
+	"stash-vr/internal/prefix"
+	"strings"
+)
+
+func newViewing(vd *library.VideoData, ev Event) *history.Record {
+	r := &history.Record{
+		SceneId:  vd.Id(),
+		Title:    vd.Title(),
+		Device:   ev.Device,
+		Start:    ev.At,
+		End:      ev.At,
+		Duration: vd.SceneParts.Files[0].Duration,
+	}
+	if vd.SceneParts.Studio != nil {
+		r.Studio = vd.SceneParts.Studio.Name
+	}
+	for _, p := range vd.SceneParts.Performers {
+		r.Performers = append(r.Performers, p.Name)
+	}
+	for _, t := range vd.SceneParts.Tags {
+		if t.Sort_name == config.Application().ExcludeSortName || strings.HasPrefix(t.Sort_name, prefix.SvrAncestor) {
+			continue
+		}
+		r.Tags = append(r.Tags, t.Name)
+	}
+	return r
+}
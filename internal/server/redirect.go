@@ -0,0 +1,21 @@
+package server
+
+import (
+	"net"
+	"net/http"
+)
+
+// redirectHandler redirects to the same url over HTTPS on the port of the HTTPS listener.
+func redirectHandler(tlsListenAddress string) http.Handler {
+	_, port, _ := net.SplitHostPort(tlsListenAddress)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = h
+		}
+		if port != "" && port != "443" {
+			host = net.JoinHostPort(host, port)
+		}
+		http.Redirect(w, r, "https://"+host+r.RequestURI, http.StatusTemporaryRedirect)
+	})
+}
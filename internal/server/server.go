@@ -8,26 +8,67 @@ import (
 	"golang.org/x/sync/errgroup"
 	"net/http"
 	"stash-vr/internal/api"
+	"stash-vr/internal/api/heresphere"
+	"stash-vr/internal/audit"
+	"stash-vr/internal/certs"
+	"stash-vr/internal/config"
+	"stash-vr/internal/device"
+	"stash-vr/internal/edits"
+	"stash-vr/internal/history"
 	"stash-vr/internal/library"
+	"stash-vr/internal/playback"
+	"stash-vr/internal/share"
 	"time"
 )
 
-func Listen(ctx context.Context, listenAddress string, libraryService *library.Service) error {
+func Listen(ctx context.Context, listenAddress string, libraryService *library.Service, playbackTracker *playback.Tracker, historyStore *history.Store, deviceStore *device.Store, shareSigner *share.Signer, editQueue *edits.Queue, heresphereState *heresphere.State, auditLog *audit.Log) error {
 	server := http.Server{
 		Addr:    listenAddress,
-		Handler: api.Router(libraryService),
+		Handler: api.Router(ctx, libraryService, playbackTracker, historyStore, deviceStore, shareSigner, editQueue, heresphereState, auditLog),
+	}
+
+	var redirectServer *http.Server
+	if config.Application().IsTLS() {
+		tlsConfig, err := certs.TLSConfig(ctx)
+		if err != nil {
+			return fmt.Errorf("tls: %w", err)
+		}
+		server.TLSConfig = tlsConfig
+		if addr := config.Application().TLSRedirectAddress; addr != "" {
+			redirectServer = &http.Server{
+				Addr:    addr,
+				Handler: redirectHandler(listenAddress),
+			}
+		}
 	}
 
 	g, gCtx := errgroup.WithContext(ctx)
 
 	g.Go(func() error {
-		log.Ctx(ctx).Info().Msg(fmt.Sprintf("Server listening at %s", listenAddress))
-		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		var err error
+		if server.TLSConfig != nil {
+			log.Ctx(ctx).Info().Msg(fmt.Sprintf("Server listening for HTTPS at %s", listenAddress))
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			log.Ctx(ctx).Info().Msg(fmt.Sprintf("Server listening at %s", listenAddress))
+			err = server.ListenAndServe()
+		}
+		if !errors.Is(err, http.ErrServerClosed) {
 			return fmt.Errorf("listen: %w", err)
 		}
 		return nil
 	})
 
+	if redirectServer != nil {
+		g.Go(func() error {
+			log.Ctx(ctx).Info().Msg(fmt.Sprintf("Redirecting HTTP at %s to HTTPS", redirectServer.Addr))
+			if err := redirectServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("listen redirect: %w", err)
+			}
+			return nil
+		})
+	}
+
 	g.Go(func() error {
 		<-gCtx.Done()
 
@@ -41,10 +82,17 @@ func Listen(ctx context.Context, listenAddress string, libraryService *library.S
 			}
 		}()
 
+		if redirectServer != nil {
+			if err := redirectServer.Shutdown(ctxShutdown); err != nil {
+				log.Ctx(ctx).Error().Err(err).Msg("Redirect server shutdown error")
+			}
+		}
 		if err := server.Shutdown(ctxShutdown); err != nil {
 			log.Ctx(ctx).Error().Err(err).Msg("Server shutdown error")
 		}
 
+		playbackTracker.Flush(ctxShutdown)
+
 		return nil
 	})
 
@@ -0,0 +1,21 @@
+package share
+
+import "context"
+
+type ctxKeyGrant struct{}
+
+func WithGrant(ctx context.Context, g Grant) context.Context {
+	return context.WithValue(ctx, ctxKeyGrant{}, g)
+}
+
+// FromContext returns the grant of the share link the request is made with, ok is false if it isn't made with one.
+func FromContext(ctx context.Context) (Grant, bool) {
+	g, ok := ctx.Value(ctxKeyGrant{}).(Grant)
+	return g, ok
+}
+
+// IsReadOnly reports whether writes to Stash must be ignored for the request, which is the case for share links.
+func IsReadOnly(ctx context.Context) bool {
+	_, ok := FromContext(ctx)
+	return ok
+}
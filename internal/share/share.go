@@ -0,0 +1,255 @@
+package share
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"stash-vr/internal/config"
+	"stash-vr/internal/library"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	keyFile        = "share.key"
+	issuedFile     = "shares.json"
+	keySize        = 32
+	tokenSeparator = "."
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid share link")
+	ErrExpired      = errors.New("share link expired")
+	ErrRevoked      = errors.New("share link revoked")
+)
+
+// Grant is what a share link gives access to. It is encoded in the link itself, Id identifies the link so that it can
+// be revoked.
+type Grant struct {
+	Id       string    `json:"i"`
+	Name     string    `json:"n,omitempty"`
+	Sections []string  `json:"s"`
+	Expires  time.Time `json:"e"`
+}
+
+// Signer issues and verifies share links. Links are valid until they expire or are revoked, only links it issued and
+// that haven't been revoked are accepted. The signing key and the issued links are saved to CONFIG_PATH, or kept in
+// memory only if CONFIG_PATH is not specified, in which case links stop working on restart.
+type Signer struct {
+	mu     sync.RWMutex
+	key    []byte
+	issued map[string]Grant
+}
+
+func Open(ctx context.Context) *Signer {
+	s := &Signer{issued: make(map[string]Grant)}
+	if config.Application().ConfigPath != "" {
+		path := resolvePath()
+		key, err := os.ReadFile(path)
+		if err == nil && len(key) == keySize {
+			s.key = key
+			s.loadIssued(ctx)
+			return s
+		}
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to read share key")
+		}
+	}
+	if err := s.Rotate(ctx); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to create share key")
+	}
+	return s
+}
+
+// Rotate replaces the signing key, invalidating all share links issued so far.
+func (s *Signer) Rotate(ctx context.Context) error {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.key = key
+	clear(s.issued)
+	s.save(ctx)
+	s.saveIssued(ctx)
+	return nil
+}
+
+// Issue returns a link for g, assigning it a new id.
+func (s *Signer) Issue(ctx context.Context, g Grant) (string, error) {
+	id := make([]byte, 9)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	g.Id = base64.RawURLEncoding.EncodeToString(id)
+	payload, err := json.Marshal(g)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	token := encoded + tokenSeparator + s.sign(encoded)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pruneIssued()
+	s.issued[g.Id] = g
+	s.saveIssued(ctx)
+	return token, nil
+}
+
+// Revoke invalidates the link with id, reporting whether it was valid.
+func (s *Signer) Revoke(ctx context.Context, id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.issued[id]; !ok {
+		return false
+	}
+	delete(s.issued, id)
+	s.saveIssued(ctx)
+	return true
+}
+
+// Issued returns the links that are still valid, the first to expire first.
+func (s *Signer) Issued() []Grant {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pruneIssued()
+	grants := slices.Collect(maps.Values(s.issued))
+	slices.SortFunc(grants, func(a, b Grant) int {
+		return a.Expires.Compare(b.Expires)
+	})
+	return grants
+}
+
+func (s *Signer) Verify(token string) (Grant, error) {
+	encoded, signature, found := strings.Cut(token, tokenSeparator)
+	if !found || !hmac.Equal([]byte(signature), []byte(s.sign(encoded))) {
+		return Grant{}, ErrInvalidToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Grant{}, ErrInvalidToken
+	}
+	var g Grant
+	if err := json.Unmarshal(payload, &g); err != nil {
+		return Grant{}, ErrInvalidToken
+	}
+	if !time.Now().Before(g.Expires) {
+		return Grant{}, ErrExpired
+	}
+	s.mu.RLock()
+	_, issued := s.issued[g.Id]
+	s.mu.RUnlock()
+	if !issued {
+		return Grant{}, ErrRevoked
+	}
+	return g, nil
+}
+
+func (s *Signer) pruneIssued() {
+	now := time.Now()
+	for id, g := range s.issued {
+		if !now.Before(g.Expires) {
+			delete(s.issued, id)
+		}
+	}
+}
+
+func (s *Signer) sign(encoded string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte("share:" + encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *Signer) save(ctx context.Context) {
+	if config.Application().ConfigPath == "" {
+		log.Ctx(ctx).Debug().Msg("CONFIG_PATH not specified, share links will not survive a restart")
+		return
+	}
+	path := resolvePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("error creating config directory")
+		return
+	}
+	if err := os.WriteFile(path, s.key, 0o600); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to save share key")
+	}
+}
+
+func (s *Signer) loadIssued(ctx context.Context) {
+	path := filepath.Join(config.Application().ConfigPath, issuedFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to read share links")
+		}
+		return
+	}
+	if err := json.Unmarshal(data, &s.issued); err != nil || s.issued == nil {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to parse share links")
+		s.issued = make(map[string]Grant)
+	}
+}
+
+func (s *Signer) saveIssued(ctx context.Context) {
+	if config.Application().ConfigPath == "" {
+		return
+	}
+	path := filepath.Join(config.Application().ConfigPath, issuedFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("error creating config directory")
+		return
+	}
+	data, err := json.MarshalIndent(s.issued, "", "  ")
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to encode share links")
+		return
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to save share links")
+	}
+}
+
+func resolvePath() string {
+	return filepath.Join(config.Application().ConfigPath, keyFile)
+}
+
+// Restrict returns the sections a share link made in ctx gives access to, or all sections if the request isn't made
+// with a share link.
+func Restrict(ctx context.Context, sections []library.Section) []library.Section {
+	g, ok := FromContext(ctx)
+	if !ok {
+		return sections
+	}
+	return slices.DeleteFunc(slices.Clone(sections), func(s library.Section) bool {
+		return !slices.Contains(g.Sections, s.Name)
+	})
+}
+
+// Allows reports whether the scene is reachable within the sections a share link made in ctx gives access to.
+func Allows(ctx context.Context, sections []library.Section, sceneId string) bool {
+	if _, ok := FromContext(ctx); !ok {
+		return true
+	}
+	for _, s := range Restrict(ctx, sections) {
+		if slices.Contains(s.Ids, sceneId) {
+			return true
+		}
+	}
+	return false
+}
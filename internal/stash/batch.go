@@ -0,0 +1,102 @@
+package stash
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Khan/genqlient/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const batchOpName = "Batch"
+
+// Batch collects mutations to send to Stash as a single request, each as an aliased field.
+type Batch struct {
+	definitions []string
+	fields      []string
+	variables   map[string]any
+}
+
+// BatchError is returned by Batch.Do when some of the mutations failed. Stash executes the mutations of a batch one by
+// one, so the others were applied.
+type BatchError struct {
+	// Failed holds the aliases of the mutations that failed.
+	Failed map[string]bool
+	Err    error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("Batch (%d mutations failed): %v", len(e.Failed), e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// Add adds a mutation taking a single argument of argType and returns the alias of its result. selection is the
+// selection set of its result, if any.
+func (b *Batch) Add(mutation string, argName string, argType string, arg any, selection string) string {
+	if b.variables == nil {
+		b.variables = make(map[string]any)
+	}
+	i := len(b.fields)
+	alias := fmt.Sprintf("m%d", i)
+	b.definitions = append(b.definitions, fmt.Sprintf("$v%d: %s", i, argType))
+	b.fields = append(b.fields, fmt.Sprintf("%s: %s(%s: $v%d)%s", alias, mutation, argName, i, selection))
+	b.variables[fmt.Sprintf("v%d", i)] = arg
+	return alias
+}
+
+func (b *Batch) Len() int {
+	return len(b.fields)
+}
+
+// Do sends all mutations, Stash executes them in order. The results of the mutations that succeeded are returned by
+// alias. If only some of them failed the error is a *BatchError, otherwise none of them were applied.
+func (b *Batch) Do(ctx context.Context, client graphql.Client) (map[string]json.RawMessage, error) {
+	if b.Len() == 0 {
+		return nil, nil
+	}
+	req := graphql.Request{
+		OpName:    batchOpName,
+		Query:     fmt.Sprintf("mutation %s(%s){%s}", batchOpName, strings.Join(b.definitions, ", "), strings.Join(b.fields, " ")),
+		Variables: b.variables,
+	}
+	var data map[string]json.RawMessage
+	err := client.MakeRequest(ctx, &req, &graphql.Response{Data: &data})
+	if err == nil {
+		return data, nil
+	}
+	failed := failedFields(err)
+	if len(failed) == 0 {
+		return nil, fmt.Errorf("Batch (%d mutations): %w", b.Len(), err)
+	}
+	for alias, result := range data {
+		if failed[alias] || string(result) == "null" {
+			delete(data, alias)
+		}
+	}
+	return data, &BatchError{Failed: failed, Err: err}
+}
+
+// failedFields returns the top level fields that failed to execute with err, none if the request failed as a whole.
+func failedFields(err error) map[string]bool {
+	var errs gqlerror.List
+	if !errors.As(err, &errs) {
+		return nil
+	}
+	failed := make(map[string]bool)
+	for _, e := range errs {
+		if len(e.Path) == 0 {
+			continue
+		}
+		if name, ok := e.Path[0].(ast.PathName); ok {
+			failed[string(name)] = true
+		}
+	}
+	return failed
+}
@@ -0,0 +1,84 @@
+package stash
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"stash-vr/internal/stash/gql"
+	"strings"
+
+	"github.com/Khan/genqlient/graphql"
+)
+
+// ErrUnsupported is returned for operations the connected Stash version has no support for.
+var ErrUnsupported = errors.New("not supported by this Stash version")
+
+// requiredSceneFields are queried for every scene, scenes can't be loaded from a Stash without them.
+var requiredSceneFields = []string{"rating100", "play_count", "resume_time", "o_counter", "sceneStreams", "captions", "interactive"}
+
+// Capabilities are the parts of the Stash schema that differ between versions Stash-VR knows how to work with.
+type Capabilities struct {
+	// SceneGroups is true if scenes have groups, which replaced movies in Stash v0.27.
+	SceneGroups bool
+	// OHistory is true for sceneAddO/sceneDeleteO, which replaced sceneIncrementO/sceneDecrementO in Stash v0.26.
+	OHistory bool
+	// PlayHistory is true for sceneAddPlay/sceneDeletePlay. Before Stash v0.26 play count could only be incremented.
+	PlayHistory bool
+	// SaveActivity is true for sceneSaveActivity, needed to sync resume time and play duration.
+	SaveActivity bool
+	// MissingSceneFields are scene fields Stash-VR queries but Stash doesn't have.
+	MissingSceneFields []string
+}
+
+// LatestCapabilities is assumed until the schema of Stash has been inspected.
+var LatestCapabilities = Capabilities{SceneGroups: true, OHistory: true, PlayHistory: true, SaveActivity: true}
+
+func DetectCapabilities(ctx context.Context, client graphql.Client) (Capabilities, error) {
+	resp, err := gql.Capabilities(ctx, client)
+	if err != nil {
+		return LatestCapabilities, err
+	}
+	if resp.Scene == nil || resp.Mutation == nil {
+		return LatestCapabilities, errors.New("schema introspection returned no types")
+	}
+
+	sceneFields := make([]string, len(resp.Scene.Fields))
+	for i, f := range resp.Scene.Fields {
+		sceneFields[i] = f.Name
+	}
+	mutations := make([]string, len(resp.Mutation.Fields))
+	for i, f := range resp.Mutation.Fields {
+		mutations[i] = f.Name
+	}
+
+	c := Capabilities{
+		SceneGroups:  slices.Contains(sceneFields, "groups"),
+		OHistory:     slices.Contains(mutations, "sceneAddO") && slices.Contains(mutations, "sceneDeleteO"),
+		PlayHistory:  slices.Contains(mutations, "sceneAddPlay") && slices.Contains(mutations, "sceneDeletePlay"),
+		SaveActivity: slices.Contains(mutations, "sceneSaveActivity"),
+	}
+	for _, f := range requiredSceneFields {
+		if !slices.Contains(sceneFields, f) {
+			c.MissingSceneFields = append(c.MissingSceneFields, f)
+		}
+	}
+	return c, nil
+}
+
+// Limitations describes features that are unavailable or work differently with the connected Stash version.
+func (c Capabilities) Limitations() []string {
+	var out []string
+	if len(c.MissingSceneFields) > 0 {
+		out = append(out, "Scenes can't be loaded, Stash is missing scene fields: "+strings.Join(c.MissingSceneFields, ", ")+". Upgrade Stash.")
+	}
+	if !c.SceneGroups {
+		out = append(out, "Movies are shown instead of groups (Stash before v0.27).")
+	}
+	if !c.PlayHistory {
+		out = append(out, "Play count can be incremented but not decremented (Stash before v0.26).")
+	}
+	if !c.SaveActivity {
+		out = append(out, "Resume time and play duration are not synced (Stash has no sceneSaveActivity).")
+	}
+	return out
+}
@@ -3,9 +3,16 @@ package stash
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
 	"github.com/Khan/genqlient/graphql"
+	"net"
 	"net/http"
+	"os"
+	"stash-vr/internal/config"
 	"stash-vr/internal/stash/gql"
+	"time"
 )
 
 type authTransport struct {
@@ -19,26 +26,98 @@ func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	return t.rt.RoundTrip(req2)
 }
 
-func NewClient(graphqlUrl string, apiKey string) graphql.Client {
+// NewClient returns a GraphQL client for Stash that retries transient errors, see resilientClient.
+func NewClient(graphqlUrl string, httpClient *http.Client) graphql.Client {
+	return newResilientClient(graphql.NewClient(graphqlUrl, httpClient))
+}
+
+// Credentials to authenticate to Stash with, either an api key or username and password. Empty if Stash doesn't
+// require authentication.
+type Credentials struct {
+	ApiKey   string
+	Username string
+	Password string
+}
+
+// NewHttpClient returns the client all requests to Stash are made with - GraphQL, images and proxied media. Requests
+// are authenticated by the api key in a header if provided, or else by a session logged in with username and password.
+// There is no overall request timeout since media is streamed through it, only timeouts for connecting and for waiting
+// on response headers.
+func NewHttpClient(graphqlUrl string, credentials Credentials) (*http.Client, error) {
+	tlsConfig, err := newTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
 	defaultTr, _ := http.DefaultTransport.(*http.Transport)
 	transport := defaultTr.Clone()
-	transport.TLSClientConfig = &tls.Config{
-		InsecureSkipVerify: true,
-	}
+	transport.TLSClientConfig = tlsConfig
+	transport.DialContext = (&net.Dialer{
+		Timeout:   config.Application().StashConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = config.Application().StashConnectTimeout
+	transport.ResponseHeaderTimeout = config.Application().StashReadTimeout
 
-	var rt http.RoundTripper = transport
-	if apiKey != "" {
-		rt = &authTransport{
-			apiKey: apiKey,
+	client := &http.Client{
+		Transport: transport,
+	}
+	switch {
+	case credentials.ApiKey != "":
+		client.Transport = &authTransport{
+			apiKey: credentials.ApiKey,
 			rt:     transport,
 		}
+	case credentials.Username != "":
+		st, err := newSessionTransport(graphqlUrl, credentials.Username, credentials.Password, transport)
+		if err != nil {
+			return nil, err
+		}
+		client.Transport = st
+		client.Jar = st.jar
+	}
+	return client, nil
+}
+
+func newTLSConfig() (*tls.Config, error) {
+	cfg := config.Application()
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: cfg.StashInsecureSkipVerify,
+	}
+
+	if cfg.StashCAFile != "" {
+		pem, err := os.ReadFile(cfg.StashCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no certificates found in CA file")
+		}
+		tlsConfig.RootCAs = pool
 	}
 
-	htc := &http.Client{
-		Transport: rt,
+	if cfg.StashClientCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.StashClientCertFile, cfg.StashClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
 	}
 
-	return graphql.NewClient(graphqlUrl, htc)
+	return tlsConfig, nil
+}
+
+// GetUsername returns the username Stash is configured with, which is only readable when logged in.
+func GetUsername(ctx context.Context, client graphql.Client) (string, error) {
+	resp, err := gql.FindCredentials(ctx, client)
+	if err != nil {
+		return "", err
+	}
+	return resp.Configuration.General.Username, nil
 }
 
 func GetVersion(ctx context.Context, client graphql.Client) (string, error) {
@@ -2,10 +2,12 @@ package stash
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/Khan/genqlient/graphql"
 	"github.com/rs/zerolog/log"
 	"stash-vr/internal/stash/gql"
+	"strings"
 )
 
 func FindOrCreateTag(ctx context.Context, client graphql.Client, name string) (string, error) {
@@ -26,3 +28,121 @@ func FindOrCreateTag(ctx context.Context, client graphql.Client, name string) (s
 	}
 	return findResponse.FindTags.Tags[0].Id, nil
 }
+
+var ErrPerformerNotFound = errors.New("performer not found")
+
+// ErrAmbiguous is returned when a name matches more than one performer, studio or group.
+var ErrAmbiguous = errors.New("ambiguous name")
+
+// FindOrCreatePerformer returns the id and name of the performer named name, or with name as alias if there is none.
+// If there is no match either it's created if create is true, otherwise ErrPerformerNotFound is returned. If several
+// performers match, ErrAmbiguous is returned.
+func FindOrCreatePerformer(ctx context.Context, client graphql.Client, name string, create bool) (string, string, error) {
+	if name == "" {
+		return "", "", fmt.Errorf("can not find or create with empty performer name")
+	}
+	findResponse, err := gql.FindPerformersByName(ctx, client, name)
+	if err != nil {
+		return "", "", fmt.Errorf("FindPerformersByName (%s): %w", name, err)
+	}
+	performers := findResponse.FindPerformers.Performers
+	names := make([]string, len(performers))
+	for i, p := range performers {
+		names[i] = p.Name
+	}
+	if i, err := match(names, name); err != nil {
+		return "", "", fmt.Errorf("performer %w", err)
+	} else if i >= 0 {
+		return performers[i].Id, performers[i].Name, nil
+	}
+	if !create {
+		return "", "", fmt.Errorf("%w: %s", ErrPerformerNotFound, name)
+	}
+	createResponse, err := gql.PerformerCreate(ctx, client, name)
+	if err != nil {
+		return "", "", fmt.Errorf("PerformerCreate (%s): %w", name, err)
+	}
+	log.Ctx(ctx).Info().Str("name", name).Str("id", createResponse.PerformerCreate.Id).Msg("Performer created in stash")
+	return createResponse.PerformerCreate.Id, name, nil
+}
+
+var ErrStudioNotFound = errors.New("studio not found")
+
+// FindStudio returns the id and name of the studio named name, or with name as alias if there is none. If several
+// studios match, ErrAmbiguous is returned.
+func FindStudio(ctx context.Context, client graphql.Client, name string) (string, string, error) {
+	findResponse, err := gql.FindStudioByName(ctx, client, name)
+	if err != nil {
+		return "", "", fmt.Errorf("FindStudioByName (%s): %w", name, err)
+	}
+	studios := findResponse.FindStudios.Studios
+	names := make([]string, len(studios))
+	for i, s := range studios {
+		names[i] = s.Name
+	}
+	i, err := match(names, name)
+	if err != nil {
+		return "", "", fmt.Errorf("studio %w", err)
+	}
+	if i < 0 {
+		return "", "", fmt.Errorf("%w: %s", ErrStudioNotFound, name)
+	}
+	return studios[i].Id, studios[i].Name, nil
+}
+
+var ErrGroupNotFound = errors.New("group not found")
+
+// FindGroup returns the id and name of the group named name. Movies are searched instead if sceneGroups is false, for
+// Stash versions before groups replaced movies. If several groups match, ErrAmbiguous is returned.
+func FindGroup(ctx context.Context, client graphql.Client, name string, sceneGroups bool) (string, string, error) {
+	var ids, names []string
+	if sceneGroups {
+		findResponse, err := gql.FindGroupsByName(ctx, client, name)
+		if err != nil {
+			return "", "", fmt.Errorf("FindGroupsByName (%s): %w", name, err)
+		}
+		for _, g := range findResponse.FindGroups.Groups {
+			ids, names = append(ids, g.Id), append(names, g.Name)
+		}
+	} else {
+		findResponse, err := gql.FindMoviesByName(ctx, client, name)
+		if err != nil {
+			return "", "", fmt.Errorf("FindMoviesByName (%s): %w", name, err)
+		}
+		for _, m := range findResponse.FindMovies.Movies {
+			ids, names = append(ids, m.Id), append(names, m.Name)
+		}
+	}
+	i, err := match(names, name)
+	if err != nil {
+		return "", "", fmt.Errorf("group %w", err)
+	}
+	if i < 0 {
+		return "", "", fmt.Errorf("%w: %s", ErrGroupNotFound, name)
+	}
+	return ids[i], names[i], nil
+}
+
+// match returns the index of the only one of names equal to name, ignoring case, or else of the only name if there is
+// just one, i.e. a match by alias. It returns -1 if there are no names and ErrAmbiguous if no single one matches.
+func match(names []string, name string) (int, error) {
+	exact := -1
+	for i, n := range names {
+		if !strings.EqualFold(n, name) {
+			continue
+		}
+		if exact >= 0 {
+			return -1, fmt.Errorf("%w: %s matches %s and %s", ErrAmbiguous, name, names[exact], n)
+		}
+		exact = i
+	}
+	switch {
+	case exact >= 0:
+		return exact, nil
+	case len(names) == 1:
+		return 0, nil
+	case len(names) > 1:
+		return -1, fmt.Errorf("%w: %s matches %s", ErrAmbiguous, name, strings.Join(names, ", "))
+	}
+	return -1, nil
+}
@@ -10,6 +10,68 @@ import (
 	"github.com/Khan/genqlient/graphql"
 )
 
+// CapabilitiesMutationType includes the requested fields of the GraphQL type __Type.
+// The GraphQL type's documentation follows.
+//
+// The fundamental unit of any GraphQL Schema is the type. There are many kinds of types in GraphQL as represented by the `__TypeKind` enum.
+//
+// Depending on the kind of a type, certain fields describe information about that type. Scalar types provide no information beyond a name, description and optional `specifiedByURL`, while Enum types provide their values. Object and Interface types provide the fields they describe. Abstract types, Union and Interface, provide the Object types possible at runtime. List and NonNull types compose other types.
+type CapabilitiesMutationType struct {
+	Fields []*CapabilitiesMutationTypeFieldsField `json:"fields"`
+}
+
+// GetFields returns CapabilitiesMutationType.Fields, and is useful for accessing the field via an interface.
+func (v *CapabilitiesMutationType) GetFields() []*CapabilitiesMutationTypeFieldsField {
+	return v.Fields
+}
+
+// CapabilitiesMutationTypeFieldsField includes the requested fields of the GraphQL type __Field.
+// The GraphQL type's documentation follows.
+//
+// Object and Interface types are described by a list of Fields, each of which has a name, potentially a list of arguments, and a return type.
+type CapabilitiesMutationTypeFieldsField struct {
+	Name string `json:"name"`
+}
+
+// GetName returns CapabilitiesMutationTypeFieldsField.Name, and is useful for accessing the field via an interface.
+func (v *CapabilitiesMutationTypeFieldsField) GetName() string { return v.Name }
+
+// CapabilitiesResponse is returned by Capabilities on success.
+type CapabilitiesResponse struct {
+	Scene    *CapabilitiesSceneType    `json:"scene"`
+	Mutation *CapabilitiesMutationType `json:"mutation"`
+}
+
+// GetScene returns CapabilitiesResponse.Scene, and is useful for accessing the field via an interface.
+func (v *CapabilitiesResponse) GetScene() *CapabilitiesSceneType { return v.Scene }
+
+// GetMutation returns CapabilitiesResponse.Mutation, and is useful for accessing the field via an interface.
+func (v *CapabilitiesResponse) GetMutation() *CapabilitiesMutationType { return v.Mutation }
+
+// CapabilitiesSceneType includes the requested fields of the GraphQL type __Type.
+// The GraphQL type's documentation follows.
+//
+// The fundamental unit of any GraphQL Schema is the type. There are many kinds of types in GraphQL as represented by the `__TypeKind` enum.
+//
+// Depending on the kind of a type, certain fields describe information about that type. Scalar types provide no information beyond a name, description and optional `specifiedByURL`, while Enum types provide their values. Object and Interface types provide the fields they describe. Abstract types, Union and Interface, provide the Object types possible at runtime. List and NonNull types compose other types.
+type CapabilitiesSceneType struct {
+	Fields []*CapabilitiesSceneTypeFieldsField `json:"fields"`
+}
+
+// GetFields returns CapabilitiesSceneType.Fields, and is useful for accessing the field via an interface.
+func (v *CapabilitiesSceneType) GetFields() []*CapabilitiesSceneTypeFieldsField { return v.Fields }
+
+// CapabilitiesSceneTypeFieldsField includes the requested fields of the GraphQL type __Field.
+// The GraphQL type's documentation follows.
+//
+// Object and Interface types are described by a list of Fields, each of which has a name, potentially a list of arguments, and a return type.
+type CapabilitiesSceneTypeFieldsField struct {
+	Name string `json:"name"`
+}
+
+// GetName returns CapabilitiesSceneTypeFieldsField.Name, and is useful for accessing the field via an interface.
+func (v *CapabilitiesSceneTypeFieldsField) GetName() string { return v.Name }
+
 type CircumcisedEnum string
 
 const (
@@ -93,6 +155,24 @@ func (v *CustomFieldCriterionInput) GetModifier() CriterionModifier { return v.M
 // GetValue returns CustomFieldCriterionInput.Value, and is useful for accessing the field via an interface.
 func (v *CustomFieldCriterionInput) GetValue() []any { return v.Value }
 
+type CustomFieldsInput struct {
+	// If populated, the entire custom fields map will be replaced with this value
+	Full *map[string]interface{} `json:"full,omitempty"`
+	// If populated, only the keys in this map will be updated
+	Partial *map[string]interface{} `json:"partial,omitempty"`
+	// Remove any keys in this list
+	Remove []string `json:"remove,omitempty"`
+}
+
+// GetFull returns CustomFieldsInput.Full, and is useful for accessing the field via an interface.
+func (v *CustomFieldsInput) GetFull() *map[string]interface{} { return v.Full }
+
+// GetPartial returns CustomFieldsInput.Partial, and is useful for accessing the field via an interface.
+func (v *CustomFieldsInput) GetPartial() *map[string]interface{} { return v.Partial }
+
+// GetRemove returns CustomFieldsInput.Remove, and is useful for accessing the field via an interface.
+func (v *CustomFieldsInput) GetRemove() []string { return v.Remove }
+
 type DateCriterionInput struct {
 	Modifier CriterionModifier `json:"modifier"`
 	Value    string            `json:"value"`
@@ -473,6 +553,72 @@ func (v *FindFilterType) GetQ() *string { return v.Q }
 // GetSort returns FindFilterType.Sort, and is useful for accessing the field via an interface.
 func (v *FindFilterType) GetSort() *string { return v.Sort }
 
+// FindGroupsByNameFindGroupsFindGroupsResultType includes the requested fields of the GraphQL type FindGroupsResultType.
+type FindGroupsByNameFindGroupsFindGroupsResultType struct {
+	Groups []*FindGroupsByNameFindGroupsFindGroupsResultTypeGroupsGroup `json:"groups"`
+}
+
+// GetGroups returns FindGroupsByNameFindGroupsFindGroupsResultType.Groups, and is useful for accessing the field via an interface.
+func (v *FindGroupsByNameFindGroupsFindGroupsResultType) GetGroups() []*FindGroupsByNameFindGroupsFindGroupsResultTypeGroupsGroup {
+	return v.Groups
+}
+
+// FindGroupsByNameFindGroupsFindGroupsResultTypeGroupsGroup includes the requested fields of the GraphQL type Group.
+type FindGroupsByNameFindGroupsFindGroupsResultTypeGroupsGroup struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// GetId returns FindGroupsByNameFindGroupsFindGroupsResultTypeGroupsGroup.Id, and is useful for accessing the field via an interface.
+func (v *FindGroupsByNameFindGroupsFindGroupsResultTypeGroupsGroup) GetId() string { return v.Id }
+
+// GetName returns FindGroupsByNameFindGroupsFindGroupsResultTypeGroupsGroup.Name, and is useful for accessing the field via an interface.
+func (v *FindGroupsByNameFindGroupsFindGroupsResultTypeGroupsGroup) GetName() string { return v.Name }
+
+// FindGroupsByNameResponse is returned by FindGroupsByName on success.
+type FindGroupsByNameResponse struct {
+	// A function which queries Group objects
+	FindGroups *FindGroupsByNameFindGroupsFindGroupsResultType `json:"findGroups"`
+}
+
+// GetFindGroups returns FindGroupsByNameResponse.FindGroups, and is useful for accessing the field via an interface.
+func (v *FindGroupsByNameResponse) GetFindGroups() *FindGroupsByNameFindGroupsFindGroupsResultType {
+	return v.FindGroups
+}
+
+// FindMoviesByNameFindMoviesFindMoviesResultType includes the requested fields of the GraphQL type FindMoviesResultType.
+type FindMoviesByNameFindMoviesFindMoviesResultType struct {
+	Movies []*FindMoviesByNameFindMoviesFindMoviesResultTypeMoviesMovie `json:"movies"`
+}
+
+// GetMovies returns FindMoviesByNameFindMoviesFindMoviesResultType.Movies, and is useful for accessing the field via an interface.
+func (v *FindMoviesByNameFindMoviesFindMoviesResultType) GetMovies() []*FindMoviesByNameFindMoviesFindMoviesResultTypeMoviesMovie {
+	return v.Movies
+}
+
+// FindMoviesByNameFindMoviesFindMoviesResultTypeMoviesMovie includes the requested fields of the GraphQL type Movie.
+type FindMoviesByNameFindMoviesFindMoviesResultTypeMoviesMovie struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// GetId returns FindMoviesByNameFindMoviesFindMoviesResultTypeMoviesMovie.Id, and is useful for accessing the field via an interface.
+func (v *FindMoviesByNameFindMoviesFindMoviesResultTypeMoviesMovie) GetId() string { return v.Id }
+
+// GetName returns FindMoviesByNameFindMoviesFindMoviesResultTypeMoviesMovie.Name, and is useful for accessing the field via an interface.
+func (v *FindMoviesByNameFindMoviesFindMoviesResultTypeMoviesMovie) GetName() string { return v.Name }
+
+// FindMoviesByNameResponse is returned by FindMoviesByName on success.
+type FindMoviesByNameResponse struct {
+	// A function which queries Movie objects
+	FindMovies *FindMoviesByNameFindMoviesFindMoviesResultType `json:"findMovies"`
+}
+
+// GetFindMovies returns FindMoviesByNameResponse.FindMovies, and is useful for accessing the field via an interface.
+func (v *FindMoviesByNameResponse) GetFindMovies() *FindMoviesByNameFindMoviesFindMoviesResultType {
+	return v.FindMovies
+}
+
 // FindPerformerByNameFindPerformersFindPerformersResultType includes the requested fields of the GraphQL type FindPerformersResultType.
 type FindPerformerByNameFindPerformersFindPerformersResultType struct {
 	Performers []*FindPerformerByNameFindPerformersFindPerformersResultTypePerformersPerformer `json:"performers"`
@@ -504,6 +650,43 @@ func (v *FindPerformerByNameResponse) GetFindPerformers() *FindPerformerByNameFi
 	return v.FindPerformers
 }
 
+// FindPerformersByNameFindPerformersFindPerformersResultType includes the requested fields of the GraphQL type FindPerformersResultType.
+type FindPerformersByNameFindPerformersFindPerformersResultType struct {
+	Performers []*FindPerformersByNameFindPerformersFindPerformersResultTypePerformersPerformer `json:"performers"`
+}
+
+// GetPerformers returns FindPerformersByNameFindPerformersFindPerformersResultType.Performers, and is useful for accessing the field via an interface.
+func (v *FindPerformersByNameFindPerformersFindPerformersResultType) GetPerformers() []*FindPerformersByNameFindPerformersFindPerformersResultTypePerformersPerformer {
+	return v.Performers
+}
+
+// FindPerformersByNameFindPerformersFindPerformersResultTypePerformersPerformer includes the requested fields of the GraphQL type Performer.
+type FindPerformersByNameFindPerformersFindPerformersResultTypePerformersPerformer struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// GetId returns FindPerformersByNameFindPerformersFindPerformersResultTypePerformersPerformer.Id, and is useful for accessing the field via an interface.
+func (v *FindPerformersByNameFindPerformersFindPerformersResultTypePerformersPerformer) GetId() string {
+	return v.Id
+}
+
+// GetName returns FindPerformersByNameFindPerformersFindPerformersResultTypePerformersPerformer.Name, and is useful for accessing the field via an interface.
+func (v *FindPerformersByNameFindPerformersFindPerformersResultTypePerformersPerformer) GetName() string {
+	return v.Name
+}
+
+// FindPerformersByNameResponse is returned by FindPerformersByName on success.
+type FindPerformersByNameResponse struct {
+	// A function which queries Performer objects
+	FindPerformers *FindPerformersByNameFindPerformersFindPerformersResultType `json:"findPerformers"`
+}
+
+// GetFindPerformers returns FindPerformersByNameResponse.FindPerformers, and is useful for accessing the field via an interface.
+func (v *FindPerformersByNameResponse) GetFindPerformers() *FindPerformersByNameFindPerformersFindPerformersResultType {
+	return v.FindPerformers
+}
+
 // FindSampleSceneCoverFindScenesFindScenesResultType includes the requested fields of the GraphQL type FindScenesResultType.
 type FindSampleSceneCoverFindScenesFindScenesResultType struct {
 	Scenes []*FindSampleSceneCoverFindScenesFindScenesResultTypeScenesScene `json:"scenes"`
@@ -838,120 +1021,143 @@ type FindSceneTagsResponse struct {
 // GetFindScene returns FindSceneTagsResponse.FindScene, and is useful for accessing the field via an interface.
 func (v *FindSceneTagsResponse) GetFindScene() *FindSceneTagsFindScene { return v.FindScene }
 
-// FindScenesFindScenesFindScenesResultType includes the requested fields of the GraphQL type FindScenesResultType.
-type FindScenesFindScenesFindScenesResultType struct {
-	Scenes []*FindScenesFindScenesFindScenesResultTypeScenesScene `json:"scenes"`
+// FindScenesWithGroupsFindScenesFindScenesResultType includes the requested fields of the GraphQL type FindScenesResultType.
+type FindScenesWithGroupsFindScenesFindScenesResultType struct {
+	Scenes []*FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene `json:"scenes"`
 }
 
-// GetScenes returns FindScenesFindScenesFindScenesResultType.Scenes, and is useful for accessing the field via an interface.
-func (v *FindScenesFindScenesFindScenesResultType) GetScenes() []*FindScenesFindScenesFindScenesResultTypeScenesScene {
+// GetScenes returns FindScenesWithGroupsFindScenesFindScenesResultType.Scenes, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultType) GetScenes() []*FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene {
 	return v.Scenes
 }
 
-// FindScenesFindScenesFindScenesResultTypeScenesScene includes the requested fields of the GraphQL type Scene.
-type FindScenesFindScenesFindScenesResultTypeScenesScene struct {
+// FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene includes the requested fields of the GraphQL type Scene.
+type FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene struct {
 	SceneParts `json:"-"`
+	Groups     []*FindScenesWithGroupsFindScenesFindScenesResultTypeScenesSceneGroupsSceneGroup `json:"groups"`
+}
+
+// GetGroups returns FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene.Groups, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene) GetGroups() []*FindScenesWithGroupsFindScenesFindScenesResultTypeScenesSceneGroupsSceneGroup {
+	return v.Groups
 }
 
-// GetId returns FindScenesFindScenesFindScenesResultTypeScenesScene.Id, and is useful for accessing the field via an interface.
-func (v *FindScenesFindScenesFindScenesResultTypeScenesScene) GetId() string { return v.SceneParts.Id }
+// GetId returns FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene.Id, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene) GetId() string {
+	return v.SceneParts.Id
+}
 
-// GetTitle returns FindScenesFindScenesFindScenesResultTypeScenesScene.Title, and is useful for accessing the field via an interface.
-func (v *FindScenesFindScenesFindScenesResultTypeScenesScene) GetTitle() *string {
+// GetTitle returns FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene.Title, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene) GetTitle() *string {
 	return v.SceneParts.Title
 }
 
-// GetRating100 returns FindScenesFindScenesFindScenesResultTypeScenesScene.Rating100, and is useful for accessing the field via an interface.
-func (v *FindScenesFindScenesFindScenesResultTypeScenesScene) GetRating100() *int {
+// GetCode returns FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene.Code, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene) GetCode() *string {
+	return v.SceneParts.Code
+}
+
+// GetDetails returns FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene.Details, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene) GetDetails() *string {
+	return v.SceneParts.Details
+}
+
+// GetRating100 returns FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene.Rating100, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene) GetRating100() *int {
 	return v.SceneParts.Rating100
 }
 
-// GetCreated_at returns FindScenesFindScenesFindScenesResultTypeScenesScene.Created_at, and is useful for accessing the field via an interface.
-func (v *FindScenesFindScenesFindScenesResultTypeScenesScene) GetCreated_at() time.Time {
+// GetCreated_at returns FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene.Created_at, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene) GetCreated_at() time.Time {
 	return v.SceneParts.Created_at
 }
 
-// GetDate returns FindScenesFindScenesFindScenesResultTypeScenesScene.Date, and is useful for accessing the field via an interface.
-func (v *FindScenesFindScenesFindScenesResultTypeScenesScene) GetDate() *string {
+// GetUpdated_at returns FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene.Updated_at, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene) GetUpdated_at() time.Time {
+	return v.SceneParts.Updated_at
+}
+
+// GetDate returns FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene.Date, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene) GetDate() *string {
 	return v.SceneParts.Date
 }
 
-// GetFiles returns FindScenesFindScenesFindScenesResultTypeScenesScene.Files, and is useful for accessing the field via an interface.
-func (v *FindScenesFindScenesFindScenesResultTypeScenesScene) GetFiles() []*ScenePartsFilesVideoFile {
+// GetFiles returns FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene.Files, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene) GetFiles() []*ScenePartsFilesVideoFile {
 	return v.SceneParts.Files
 }
 
-// GetStudio returns FindScenesFindScenesFindScenesResultTypeScenesScene.Studio, and is useful for accessing the field via an interface.
-func (v *FindScenesFindScenesFindScenesResultTypeScenesScene) GetStudio() *ScenePartsStudio {
+// GetStudio returns FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene.Studio, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene) GetStudio() *ScenePartsStudio {
 	return v.SceneParts.Studio
 }
 
-// GetScene_markers returns FindScenesFindScenesFindScenesResultTypeScenesScene.Scene_markers, and is useful for accessing the field via an interface.
-func (v *FindScenesFindScenesFindScenesResultTypeScenesScene) GetScene_markers() []*ScenePartsScene_markersSceneMarker {
+// GetScene_markers returns FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene.Scene_markers, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene) GetScene_markers() []*ScenePartsScene_markersSceneMarker {
 	return v.SceneParts.Scene_markers
 }
 
-// GetPerformers returns FindScenesFindScenesFindScenesResultTypeScenesScene.Performers, and is useful for accessing the field via an interface.
-func (v *FindScenesFindScenesFindScenesResultTypeScenesScene) GetPerformers() []*ScenePartsPerformersPerformer {
+// GetPerformers returns FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene.Performers, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene) GetPerformers() []*ScenePartsPerformersPerformer {
 	return v.SceneParts.Performers
 }
 
-// GetGroups returns FindScenesFindScenesFindScenesResultTypeScenesScene.Groups, and is useful for accessing the field via an interface.
-func (v *FindScenesFindScenesFindScenesResultTypeScenesScene) GetGroups() []*ScenePartsGroupsSceneGroup {
-	return v.SceneParts.Groups
+// GetPlay_count returns FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene.Play_count, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene) GetPlay_count() *int {
+	return v.SceneParts.Play_count
 }
 
-// GetPlay_count returns FindScenesFindScenesFindScenesResultTypeScenesScene.Play_count, and is useful for accessing the field via an interface.
-func (v *FindScenesFindScenesFindScenesResultTypeScenesScene) GetPlay_count() *int {
-	return v.SceneParts.Play_count
+// GetResume_time returns FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene.Resume_time, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene) GetResume_time() *float64 {
+	return v.SceneParts.Resume_time
 }
 
-// GetO_counter returns FindScenesFindScenesFindScenesResultTypeScenesScene.O_counter, and is useful for accessing the field via an interface.
-func (v *FindScenesFindScenesFindScenesResultTypeScenesScene) GetO_counter() *int {
+// GetO_counter returns FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene.O_counter, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene) GetO_counter() *int {
 	return v.SceneParts.O_counter
 }
 
-// GetOrganized returns FindScenesFindScenesFindScenesResultTypeScenesScene.Organized, and is useful for accessing the field via an interface.
-func (v *FindScenesFindScenesFindScenesResultTypeScenesScene) GetOrganized() bool {
+// GetOrganized returns FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene.Organized, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene) GetOrganized() bool {
 	return v.SceneParts.Organized
 }
 
-// GetPaths returns FindScenesFindScenesFindScenesResultTypeScenesScene.Paths, and is useful for accessing the field via an interface.
-func (v *FindScenesFindScenesFindScenesResultTypeScenesScene) GetPaths() *ScenePartsPathsScenePathsType {
+// GetPaths returns FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene.Paths, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene) GetPaths() *ScenePartsPathsScenePathsType {
 	return v.SceneParts.Paths
 }
 
-// GetSceneStreams returns FindScenesFindScenesFindScenesResultTypeScenesScene.SceneStreams, and is useful for accessing the field via an interface.
-func (v *FindScenesFindScenesFindScenesResultTypeScenesScene) GetSceneStreams() []*ScenePartsSceneStreamsSceneStreamEndpoint {
+// GetSceneStreams returns FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene.SceneStreams, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene) GetSceneStreams() []*ScenePartsSceneStreamsSceneStreamEndpoint {
 	return v.SceneParts.SceneStreams
 }
 
-// GetCaptions returns FindScenesFindScenesFindScenesResultTypeScenesScene.Captions, and is useful for accessing the field via an interface.
-func (v *FindScenesFindScenesFindScenesResultTypeScenesScene) GetCaptions() []*ScenePartsCaptionsVideoCaption {
+// GetCaptions returns FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene.Captions, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene) GetCaptions() []*ScenePartsCaptionsVideoCaption {
 	return v.SceneParts.Captions
 }
 
-// GetInteractive returns FindScenesFindScenesFindScenesResultTypeScenesScene.Interactive, and is useful for accessing the field via an interface.
-func (v *FindScenesFindScenesFindScenesResultTypeScenesScene) GetInteractive() bool {
+// GetInteractive returns FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene.Interactive, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene) GetInteractive() bool {
 	return v.SceneParts.Interactive
 }
 
-// GetTags returns FindScenesFindScenesFindScenesResultTypeScenesScene.Tags, and is useful for accessing the field via an interface.
-func (v *FindScenesFindScenesFindScenesResultTypeScenesScene) GetTags() []*TagPartsArrayTagsTag {
+// GetTags returns FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene.Tags, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene) GetTags() []*TagPartsArrayTagsTag {
 	return v.SceneParts.TagPartsArray.Tags
 }
 
-func (v *FindScenesFindScenesFindScenesResultTypeScenesScene) UnmarshalJSON(b []byte) error {
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene) UnmarshalJSON(b []byte) error {
 
 	if string(b) == "null" {
 		return nil
 	}
 
 	var firstPass struct {
-		*FindScenesFindScenesFindScenesResultTypeScenesScene
+		*FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene
 		graphql.NoUnmarshalJSON
 	}
-	firstPass.FindScenesFindScenesFindScenesResultTypeScenesScene = v
+	firstPass.FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene = v
 
 	err := json.Unmarshal(b, &firstPass)
 	if err != nil {
@@ -966,15 +1172,23 @@ func (v *FindScenesFindScenesFindScenesResultTypeScenesScene) UnmarshalJSON(b []
 	return nil
 }
 
-type __premarshalFindScenesFindScenesFindScenesResultTypeScenesScene struct {
+type __premarshalFindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene struct {
+	Groups []*FindScenesWithGroupsFindScenesFindScenesResultTypeScenesSceneGroupsSceneGroup `json:"groups"`
+
 	Id string `json:"id"`
 
 	Title *string `json:"title"`
 
+	Code *string `json:"code"`
+
+	Details *string `json:"details"`
+
 	Rating100 *int `json:"rating100"`
 
 	Created_at time.Time `json:"created_at"`
 
+	Updated_at time.Time `json:"updated_at"`
+
 	Date *string `json:"date"`
 
 	Files []*ScenePartsFilesVideoFile `json:"files"`
@@ -985,10 +1199,10 @@ type __premarshalFindScenesFindScenesFindScenesResultTypeScenesScene struct {
 
 	Performers []*ScenePartsPerformersPerformer `json:"performers"`
 
-	Groups []*ScenePartsGroupsSceneGroup `json:"groups"`
-
 	Play_count *int `json:"play_count"`
 
+	Resume_time *float64 `json:"resume_time"`
+
 	O_counter *int `json:"o_counter"`
 
 	Organized bool `json:"organized"`
@@ -1004,7 +1218,7 @@ type __premarshalFindScenesFindScenesFindScenesResultTypeScenesScene struct {
 	Tags []*TagPartsArrayTagsTag `json:"tags"`
 }
 
-func (v *FindScenesFindScenesFindScenesResultTypeScenesScene) MarshalJSON() ([]byte, error) {
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene) MarshalJSON() ([]byte, error) {
 	premarshaled, err := v.__premarshalJSON()
 	if err != nil {
 		return nil, err
@@ -1012,20 +1226,24 @@ func (v *FindScenesFindScenesFindScenesResultTypeScenesScene) MarshalJSON() ([]b
 	return json.Marshal(premarshaled)
 }
 
-func (v *FindScenesFindScenesFindScenesResultTypeScenesScene) __premarshalJSON() (*__premarshalFindScenesFindScenesFindScenesResultTypeScenesScene, error) {
-	var retval __premarshalFindScenesFindScenesFindScenesResultTypeScenesScene
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene) __premarshalJSON() (*__premarshalFindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene, error) {
+	var retval __premarshalFindScenesWithGroupsFindScenesFindScenesResultTypeScenesScene
 
+	retval.Groups = v.Groups
 	retval.Id = v.SceneParts.Id
 	retval.Title = v.SceneParts.Title
+	retval.Code = v.SceneParts.Code
+	retval.Details = v.SceneParts.Details
 	retval.Rating100 = v.SceneParts.Rating100
 	retval.Created_at = v.SceneParts.Created_at
+	retval.Updated_at = v.SceneParts.Updated_at
 	retval.Date = v.SceneParts.Date
 	retval.Files = v.SceneParts.Files
 	retval.Studio = v.SceneParts.Studio
 	retval.Scene_markers = v.SceneParts.Scene_markers
 	retval.Performers = v.SceneParts.Performers
-	retval.Groups = v.SceneParts.Groups
 	retval.Play_count = v.SceneParts.Play_count
+	retval.Resume_time = v.SceneParts.Resume_time
 	retval.O_counter = v.SceneParts.O_counter
 	retval.Organized = v.SceneParts.Organized
 	retval.Paths = v.SceneParts.Paths
@@ -1036,123 +1254,186 @@ func (v *FindScenesFindScenesFindScenesResultTypeScenesScene) __premarshalJSON()
 	return &retval, nil
 }
 
-// FindScenesResponse is returned by FindScenes on success.
-type FindScenesResponse struct {
+// FindScenesWithGroupsFindScenesFindScenesResultTypeScenesSceneGroupsSceneGroup includes the requested fields of the GraphQL type SceneGroup.
+type FindScenesWithGroupsFindScenesFindScenesResultTypeScenesSceneGroupsSceneGroup struct {
+	Group       *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesSceneGroupsSceneGroupGroup `json:"group"`
+	Scene_index *int                                                                                `json:"scene_index"`
+}
+
+// GetGroup returns FindScenesWithGroupsFindScenesFindScenesResultTypeScenesSceneGroupsSceneGroup.Group, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesSceneGroupsSceneGroup) GetGroup() *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesSceneGroupsSceneGroupGroup {
+	return v.Group
+}
+
+// GetScene_index returns FindScenesWithGroupsFindScenesFindScenesResultTypeScenesSceneGroupsSceneGroup.Scene_index, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesSceneGroupsSceneGroup) GetScene_index() *int {
+	return v.Scene_index
+}
+
+// FindScenesWithGroupsFindScenesFindScenesResultTypeScenesSceneGroupsSceneGroupGroup includes the requested fields of the GraphQL type Group.
+type FindScenesWithGroupsFindScenesFindScenesResultTypeScenesSceneGroupsSceneGroupGroup struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// GetId returns FindScenesWithGroupsFindScenesFindScenesResultTypeScenesSceneGroupsSceneGroupGroup.Id, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesSceneGroupsSceneGroupGroup) GetId() string {
+	return v.Id
+}
+
+// GetName returns FindScenesWithGroupsFindScenesFindScenesResultTypeScenesSceneGroupsSceneGroupGroup.Name, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsFindScenesFindScenesResultTypeScenesSceneGroupsSceneGroupGroup) GetName() string {
+	return v.Name
+}
+
+// FindScenesWithGroupsResponse is returned by FindScenesWithGroups on success.
+type FindScenesWithGroupsResponse struct {
 	// A function which queries Scene objects
-	FindScenes *FindScenesFindScenesFindScenesResultType `json:"findScenes"`
+	FindScenes *FindScenesWithGroupsFindScenesFindScenesResultType `json:"findScenes"`
 }
 
-// GetFindScenes returns FindScenesResponse.FindScenes, and is useful for accessing the field via an interface.
-func (v *FindScenesResponse) GetFindScenes() *FindScenesFindScenesFindScenesResultType {
+// GetFindScenes returns FindScenesWithGroupsResponse.FindScenes, and is useful for accessing the field via an interface.
+func (v *FindScenesWithGroupsResponse) GetFindScenes() *FindScenesWithGroupsFindScenesFindScenesResultType {
 	return v.FindScenes
 }
 
-// FindStudioByNameFindStudiosFindStudiosResultType includes the requested fields of the GraphQL type FindStudiosResultType.
-type FindStudioByNameFindStudiosFindStudiosResultType struct {
-	Studios []*FindStudioByNameFindStudiosFindStudiosResultTypeStudiosStudio `json:"studios"`
+// FindScenesWithMoviesFindScenesFindScenesResultType includes the requested fields of the GraphQL type FindScenesResultType.
+type FindScenesWithMoviesFindScenesFindScenesResultType struct {
+	Scenes []*FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene `json:"scenes"`
 }
 
-// GetStudios returns FindStudioByNameFindStudiosFindStudiosResultType.Studios, and is useful for accessing the field via an interface.
-func (v *FindStudioByNameFindStudiosFindStudiosResultType) GetStudios() []*FindStudioByNameFindStudiosFindStudiosResultTypeStudiosStudio {
-	return v.Studios
+// GetScenes returns FindScenesWithMoviesFindScenesFindScenesResultType.Scenes, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultType) GetScenes() []*FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene {
+	return v.Scenes
 }
 
-// FindStudioByNameFindStudiosFindStudiosResultTypeStudiosStudio includes the requested fields of the GraphQL type Studio.
-type FindStudioByNameFindStudiosFindStudiosResultTypeStudiosStudio struct {
-	Id string `json:"id"`
+// FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene includes the requested fields of the GraphQL type Scene.
+type FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene struct {
+	SceneParts `json:"-"`
+	Movies     []*FindScenesWithMoviesFindScenesFindScenesResultTypeScenesSceneMoviesSceneMovie `json:"movies"`
 }
 
-// GetId returns FindStudioByNameFindStudiosFindStudiosResultTypeStudiosStudio.Id, and is useful for accessing the field via an interface.
-func (v *FindStudioByNameFindStudiosFindStudiosResultTypeStudiosStudio) GetId() string { return v.Id }
+// GetMovies returns FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene.Movies, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene) GetMovies() []*FindScenesWithMoviesFindScenesFindScenesResultTypeScenesSceneMoviesSceneMovie {
+	return v.Movies
+}
 
-// FindStudioByNameResponse is returned by FindStudioByName on success.
-type FindStudioByNameResponse struct {
-	// A function which queries Studio objects
-	FindStudios *FindStudioByNameFindStudiosFindStudiosResultType `json:"findStudios"`
+// GetId returns FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene.Id, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene) GetId() string {
+	return v.SceneParts.Id
 }
 
-// GetFindStudios returns FindStudioByNameResponse.FindStudios, and is useful for accessing the field via an interface.
-func (v *FindStudioByNameResponse) GetFindStudios() *FindStudioByNameFindStudiosFindStudiosResultType {
-	return v.FindStudios
+// GetTitle returns FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene.Title, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene) GetTitle() *string {
+	return v.SceneParts.Title
 }
 
-// FindTagByNameFindTagsFindTagsResultType includes the requested fields of the GraphQL type FindTagsResultType.
-type FindTagByNameFindTagsFindTagsResultType struct {
-	Tags []*FindTagByNameFindTagsFindTagsResultTypeTagsTag `json:"tags"`
+// GetCode returns FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene.Code, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene) GetCode() *string {
+	return v.SceneParts.Code
 }
 
-// GetTags returns FindTagByNameFindTagsFindTagsResultType.Tags, and is useful for accessing the field via an interface.
-func (v *FindTagByNameFindTagsFindTagsResultType) GetTags() []*FindTagByNameFindTagsFindTagsResultTypeTagsTag {
-	return v.Tags
+// GetDetails returns FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene.Details, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene) GetDetails() *string {
+	return v.SceneParts.Details
 }
 
-// FindTagByNameFindTagsFindTagsResultTypeTagsTag includes the requested fields of the GraphQL type Tag.
-type FindTagByNameFindTagsFindTagsResultTypeTagsTag struct {
-	Id string `json:"id"`
+// GetRating100 returns FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene.Rating100, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene) GetRating100() *int {
+	return v.SceneParts.Rating100
 }
 
-// GetId returns FindTagByNameFindTagsFindTagsResultTypeTagsTag.Id, and is useful for accessing the field via an interface.
-func (v *FindTagByNameFindTagsFindTagsResultTypeTagsTag) GetId() string { return v.Id }
+// GetCreated_at returns FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene.Created_at, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene) GetCreated_at() time.Time {
+	return v.SceneParts.Created_at
+}
 
-// FindTagByNameResponse is returned by FindTagByName on success.
-type FindTagByNameResponse struct {
-	FindTags *FindTagByNameFindTagsFindTagsResultType `json:"findTags"`
+// GetUpdated_at returns FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene.Updated_at, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene) GetUpdated_at() time.Time {
+	return v.SceneParts.Updated_at
 }
 
-// GetFindTags returns FindTagByNameResponse.FindTags, and is useful for accessing the field via an interface.
-func (v *FindTagByNameResponse) GetFindTags() *FindTagByNameFindTagsFindTagsResultType {
-	return v.FindTags
+// GetDate returns FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene.Date, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene) GetDate() *string {
+	return v.SceneParts.Date
 }
 
-// FindTagsFindTagsFindTagsResultType includes the requested fields of the GraphQL type FindTagsResultType.
-type FindTagsFindTagsFindTagsResultType struct {
-	Tags []*FindTagsFindTagsFindTagsResultTypeTagsTag `json:"tags"`
+// GetFiles returns FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene.Files, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene) GetFiles() []*ScenePartsFilesVideoFile {
+	return v.SceneParts.Files
 }
 
-// GetTags returns FindTagsFindTagsFindTagsResultType.Tags, and is useful for accessing the field via an interface.
-func (v *FindTagsFindTagsFindTagsResultType) GetTags() []*FindTagsFindTagsFindTagsResultTypeTagsTag {
-	return v.Tags
+// GetStudio returns FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene.Studio, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene) GetStudio() *ScenePartsStudio {
+	return v.SceneParts.Studio
 }
 
-// FindTagsFindTagsFindTagsResultTypeTagsTag includes the requested fields of the GraphQL type Tag.
-type FindTagsFindTagsFindTagsResultTypeTagsTag struct {
-	TagParts    `json:"-"`
-	Scene_count int `json:"scene_count"`
+// GetScene_markers returns FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene.Scene_markers, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene) GetScene_markers() []*ScenePartsScene_markersSceneMarker {
+	return v.SceneParts.Scene_markers
 }
 
-// GetScene_count returns FindTagsFindTagsFindTagsResultTypeTagsTag.Scene_count, and is useful for accessing the field via an interface.
-func (v *FindTagsFindTagsFindTagsResultTypeTagsTag) GetScene_count() int { return v.Scene_count }
+// GetPerformers returns FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene.Performers, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene) GetPerformers() []*ScenePartsPerformersPerformer {
+	return v.SceneParts.Performers
+}
 
-// GetId returns FindTagsFindTagsFindTagsResultTypeTagsTag.Id, and is useful for accessing the field via an interface.
-func (v *FindTagsFindTagsFindTagsResultTypeTagsTag) GetId() string { return v.TagParts.Id }
+// GetPlay_count returns FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene.Play_count, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene) GetPlay_count() *int {
+	return v.SceneParts.Play_count
+}
 
-// GetName returns FindTagsFindTagsFindTagsResultTypeTagsTag.Name, and is useful for accessing the field via an interface.
-func (v *FindTagsFindTagsFindTagsResultTypeTagsTag) GetName() string { return v.TagParts.Name }
+// GetResume_time returns FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene.Resume_time, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene) GetResume_time() *float64 {
+	return v.SceneParts.Resume_time
+}
 
-// GetSort_name returns FindTagsFindTagsFindTagsResultTypeTagsTag.Sort_name, and is useful for accessing the field via an interface.
-func (v *FindTagsFindTagsFindTagsResultTypeTagsTag) GetSort_name() string {
-	return v.TagParts.Sort_name
+// GetO_counter returns FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene.O_counter, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene) GetO_counter() *int {
+	return v.SceneParts.O_counter
 }
 
-// GetAliases returns FindTagsFindTagsFindTagsResultTypeTagsTag.Aliases, and is useful for accessing the field via an interface.
-func (v *FindTagsFindTagsFindTagsResultTypeTagsTag) GetAliases() []string { return v.TagParts.Aliases }
+// GetOrganized returns FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene.Organized, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene) GetOrganized() bool {
+	return v.SceneParts.Organized
+}
 
-// GetParents returns FindTagsFindTagsFindTagsResultTypeTagsTag.Parents, and is useful for accessing the field via an interface.
-func (v *FindTagsFindTagsFindTagsResultTypeTagsTag) GetParents() []*TagPartsParentsTag {
-	return v.TagParts.Parents
+// GetPaths returns FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene.Paths, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene) GetPaths() *ScenePartsPathsScenePathsType {
+	return v.SceneParts.Paths
 }
 
-func (v *FindTagsFindTagsFindTagsResultTypeTagsTag) UnmarshalJSON(b []byte) error {
+// GetSceneStreams returns FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene.SceneStreams, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene) GetSceneStreams() []*ScenePartsSceneStreamsSceneStreamEndpoint {
+	return v.SceneParts.SceneStreams
+}
+
+// GetCaptions returns FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene.Captions, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene) GetCaptions() []*ScenePartsCaptionsVideoCaption {
+	return v.SceneParts.Captions
+}
+
+// GetInteractive returns FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene.Interactive, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene) GetInteractive() bool {
+	return v.SceneParts.Interactive
+}
+
+// GetTags returns FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene.Tags, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene) GetTags() []*TagPartsArrayTagsTag {
+	return v.SceneParts.TagPartsArray.Tags
+}
+
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene) UnmarshalJSON(b []byte) error {
 
 	if string(b) == "null" {
 		return nil
 	}
 
 	var firstPass struct {
-		*FindTagsFindTagsFindTagsResultTypeTagsTag
+		*FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene
 		graphql.NoUnmarshalJSON
 	}
-	firstPass.FindTagsFindTagsFindTagsResultTypeTagsTag = v
+	firstPass.FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene = v
 
 	err := json.Unmarshal(b, &firstPass)
 	if err != nil {
@@ -1160,28 +1441,60 @@ func (v *FindTagsFindTagsFindTagsResultTypeTagsTag) UnmarshalJSON(b []byte) erro
 	}
 
 	err = json.Unmarshal(
-		b, &v.TagParts)
+		b, &v.SceneParts)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-type __premarshalFindTagsFindTagsFindTagsResultTypeTagsTag struct {
-	Scene_count int `json:"scene_count"`
+type __premarshalFindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene struct {
+	Movies []*FindScenesWithMoviesFindScenesFindScenesResultTypeScenesSceneMoviesSceneMovie `json:"movies"`
 
 	Id string `json:"id"`
 
-	Name string `json:"name"`
+	Title *string `json:"title"`
 
-	Sort_name string `json:"sort_name"`
+	Code *string `json:"code"`
 
-	Aliases []string `json:"aliases"`
+	Details *string `json:"details"`
 
-	Parents []*TagPartsParentsTag `json:"parents"`
+	Rating100 *int `json:"rating100"`
+
+	Created_at time.Time `json:"created_at"`
+
+	Updated_at time.Time `json:"updated_at"`
+
+	Date *string `json:"date"`
+
+	Files []*ScenePartsFilesVideoFile `json:"files"`
+
+	Studio *ScenePartsStudio `json:"studio"`
+
+	Scene_markers []*ScenePartsScene_markersSceneMarker `json:"scene_markers"`
+
+	Performers []*ScenePartsPerformersPerformer `json:"performers"`
+
+	Play_count *int `json:"play_count"`
+
+	Resume_time *float64 `json:"resume_time"`
+
+	O_counter *int `json:"o_counter"`
+
+	Organized bool `json:"organized"`
+
+	Paths *ScenePartsPathsScenePathsType `json:"paths"`
+
+	SceneStreams []*ScenePartsSceneStreamsSceneStreamEndpoint `json:"sceneStreams"`
+
+	Captions []*ScenePartsCaptionsVideoCaption `json:"captions"`
+
+	Interactive bool `json:"interactive"`
+
+	Tags []*TagPartsArrayTagsTag `json:"tags"`
 }
 
-func (v *FindTagsFindTagsFindTagsResultTypeTagsTag) MarshalJSON() ([]byte, error) {
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene) MarshalJSON() ([]byte, error) {
 	premarshaled, err := v.__premarshalJSON()
 	if err != nil {
 		return nil, err
@@ -1189,13 +1502,232 @@ func (v *FindTagsFindTagsFindTagsResultTypeTagsTag) MarshalJSON() ([]byte, error
 	return json.Marshal(premarshaled)
 }
 
-func (v *FindTagsFindTagsFindTagsResultTypeTagsTag) __premarshalJSON() (*__premarshalFindTagsFindTagsFindTagsResultTypeTagsTag, error) {
-	var retval __premarshalFindTagsFindTagsFindTagsResultTypeTagsTag
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene) __premarshalJSON() (*__premarshalFindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene, error) {
+	var retval __premarshalFindScenesWithMoviesFindScenesFindScenesResultTypeScenesScene
 
-	retval.Scene_count = v.Scene_count
-	retval.Id = v.TagParts.Id
-	retval.Name = v.TagParts.Name
-	retval.Sort_name = v.TagParts.Sort_name
+	retval.Movies = v.Movies
+	retval.Id = v.SceneParts.Id
+	retval.Title = v.SceneParts.Title
+	retval.Code = v.SceneParts.Code
+	retval.Details = v.SceneParts.Details
+	retval.Rating100 = v.SceneParts.Rating100
+	retval.Created_at = v.SceneParts.Created_at
+	retval.Updated_at = v.SceneParts.Updated_at
+	retval.Date = v.SceneParts.Date
+	retval.Files = v.SceneParts.Files
+	retval.Studio = v.SceneParts.Studio
+	retval.Scene_markers = v.SceneParts.Scene_markers
+	retval.Performers = v.SceneParts.Performers
+	retval.Play_count = v.SceneParts.Play_count
+	retval.Resume_time = v.SceneParts.Resume_time
+	retval.O_counter = v.SceneParts.O_counter
+	retval.Organized = v.SceneParts.Organized
+	retval.Paths = v.SceneParts.Paths
+	retval.SceneStreams = v.SceneParts.SceneStreams
+	retval.Captions = v.SceneParts.Captions
+	retval.Interactive = v.SceneParts.Interactive
+	retval.Tags = v.SceneParts.TagPartsArray.Tags
+	return &retval, nil
+}
+
+// FindScenesWithMoviesFindScenesFindScenesResultTypeScenesSceneMoviesSceneMovie includes the requested fields of the GraphQL type SceneMovie.
+type FindScenesWithMoviesFindScenesFindScenesResultTypeScenesSceneMoviesSceneMovie struct {
+	Movie       *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesSceneMoviesSceneMovieMovie `json:"movie"`
+	Scene_index *int                                                                                `json:"scene_index"`
+}
+
+// GetMovie returns FindScenesWithMoviesFindScenesFindScenesResultTypeScenesSceneMoviesSceneMovie.Movie, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesSceneMoviesSceneMovie) GetMovie() *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesSceneMoviesSceneMovieMovie {
+	return v.Movie
+}
+
+// GetScene_index returns FindScenesWithMoviesFindScenesFindScenesResultTypeScenesSceneMoviesSceneMovie.Scene_index, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesSceneMoviesSceneMovie) GetScene_index() *int {
+	return v.Scene_index
+}
+
+// FindScenesWithMoviesFindScenesFindScenesResultTypeScenesSceneMoviesSceneMovieMovie includes the requested fields of the GraphQL type Movie.
+type FindScenesWithMoviesFindScenesFindScenesResultTypeScenesSceneMoviesSceneMovieMovie struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// GetId returns FindScenesWithMoviesFindScenesFindScenesResultTypeScenesSceneMoviesSceneMovieMovie.Id, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesSceneMoviesSceneMovieMovie) GetId() string {
+	return v.Id
+}
+
+// GetName returns FindScenesWithMoviesFindScenesFindScenesResultTypeScenesSceneMoviesSceneMovieMovie.Name, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesFindScenesFindScenesResultTypeScenesSceneMoviesSceneMovieMovie) GetName() string {
+	return v.Name
+}
+
+// FindScenesWithMoviesResponse is returned by FindScenesWithMovies on success.
+type FindScenesWithMoviesResponse struct {
+	// A function which queries Scene objects
+	FindScenes *FindScenesWithMoviesFindScenesFindScenesResultType `json:"findScenes"`
+}
+
+// GetFindScenes returns FindScenesWithMoviesResponse.FindScenes, and is useful for accessing the field via an interface.
+func (v *FindScenesWithMoviesResponse) GetFindScenes() *FindScenesWithMoviesFindScenesFindScenesResultType {
+	return v.FindScenes
+}
+
+// FindStudioByNameFindStudiosFindStudiosResultType includes the requested fields of the GraphQL type FindStudiosResultType.
+type FindStudioByNameFindStudiosFindStudiosResultType struct {
+	Studios []*FindStudioByNameFindStudiosFindStudiosResultTypeStudiosStudio `json:"studios"`
+}
+
+// GetStudios returns FindStudioByNameFindStudiosFindStudiosResultType.Studios, and is useful for accessing the field via an interface.
+func (v *FindStudioByNameFindStudiosFindStudiosResultType) GetStudios() []*FindStudioByNameFindStudiosFindStudiosResultTypeStudiosStudio {
+	return v.Studios
+}
+
+// FindStudioByNameFindStudiosFindStudiosResultTypeStudiosStudio includes the requested fields of the GraphQL type Studio.
+type FindStudioByNameFindStudiosFindStudiosResultTypeStudiosStudio struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// GetId returns FindStudioByNameFindStudiosFindStudiosResultTypeStudiosStudio.Id, and is useful for accessing the field via an interface.
+func (v *FindStudioByNameFindStudiosFindStudiosResultTypeStudiosStudio) GetId() string { return v.Id }
+
+// GetName returns FindStudioByNameFindStudiosFindStudiosResultTypeStudiosStudio.Name, and is useful for accessing the field via an interface.
+func (v *FindStudioByNameFindStudiosFindStudiosResultTypeStudiosStudio) GetName() string {
+	return v.Name
+}
+
+// FindStudioByNameResponse is returned by FindStudioByName on success.
+type FindStudioByNameResponse struct {
+	// A function which queries Studio objects
+	FindStudios *FindStudioByNameFindStudiosFindStudiosResultType `json:"findStudios"`
+}
+
+// GetFindStudios returns FindStudioByNameResponse.FindStudios, and is useful for accessing the field via an interface.
+func (v *FindStudioByNameResponse) GetFindStudios() *FindStudioByNameFindStudiosFindStudiosResultType {
+	return v.FindStudios
+}
+
+// FindTagByNameFindTagsFindTagsResultType includes the requested fields of the GraphQL type FindTagsResultType.
+type FindTagByNameFindTagsFindTagsResultType struct {
+	Tags []*FindTagByNameFindTagsFindTagsResultTypeTagsTag `json:"tags"`
+}
+
+// GetTags returns FindTagByNameFindTagsFindTagsResultType.Tags, and is useful for accessing the field via an interface.
+func (v *FindTagByNameFindTagsFindTagsResultType) GetTags() []*FindTagByNameFindTagsFindTagsResultTypeTagsTag {
+	return v.Tags
+}
+
+// FindTagByNameFindTagsFindTagsResultTypeTagsTag includes the requested fields of the GraphQL type Tag.
+type FindTagByNameFindTagsFindTagsResultTypeTagsTag struct {
+	Id string `json:"id"`
+}
+
+// GetId returns FindTagByNameFindTagsFindTagsResultTypeTagsTag.Id, and is useful for accessing the field via an interface.
+func (v *FindTagByNameFindTagsFindTagsResultTypeTagsTag) GetId() string { return v.Id }
+
+// FindTagByNameResponse is returned by FindTagByName on success.
+type FindTagByNameResponse struct {
+	FindTags *FindTagByNameFindTagsFindTagsResultType `json:"findTags"`
+}
+
+// GetFindTags returns FindTagByNameResponse.FindTags, and is useful for accessing the field via an interface.
+func (v *FindTagByNameResponse) GetFindTags() *FindTagByNameFindTagsFindTagsResultType {
+	return v.FindTags
+}
+
+// FindTagsFindTagsFindTagsResultType includes the requested fields of the GraphQL type FindTagsResultType.
+type FindTagsFindTagsFindTagsResultType struct {
+	Tags []*FindTagsFindTagsFindTagsResultTypeTagsTag `json:"tags"`
+}
+
+// GetTags returns FindTagsFindTagsFindTagsResultType.Tags, and is useful for accessing the field via an interface.
+func (v *FindTagsFindTagsFindTagsResultType) GetTags() []*FindTagsFindTagsFindTagsResultTypeTagsTag {
+	return v.Tags
+}
+
+// FindTagsFindTagsFindTagsResultTypeTagsTag includes the requested fields of the GraphQL type Tag.
+type FindTagsFindTagsFindTagsResultTypeTagsTag struct {
+	TagParts    `json:"-"`
+	Scene_count int `json:"scene_count"`
+}
+
+// GetScene_count returns FindTagsFindTagsFindTagsResultTypeTagsTag.Scene_count, and is useful for accessing the field via an interface.
+func (v *FindTagsFindTagsFindTagsResultTypeTagsTag) GetScene_count() int { return v.Scene_count }
+
+// GetId returns FindTagsFindTagsFindTagsResultTypeTagsTag.Id, and is useful for accessing the field via an interface.
+func (v *FindTagsFindTagsFindTagsResultTypeTagsTag) GetId() string { return v.TagParts.Id }
+
+// GetName returns FindTagsFindTagsFindTagsResultTypeTagsTag.Name, and is useful for accessing the field via an interface.
+func (v *FindTagsFindTagsFindTagsResultTypeTagsTag) GetName() string { return v.TagParts.Name }
+
+// GetSort_name returns FindTagsFindTagsFindTagsResultTypeTagsTag.Sort_name, and is useful for accessing the field via an interface.
+func (v *FindTagsFindTagsFindTagsResultTypeTagsTag) GetSort_name() string {
+	return v.TagParts.Sort_name
+}
+
+// GetAliases returns FindTagsFindTagsFindTagsResultTypeTagsTag.Aliases, and is useful for accessing the field via an interface.
+func (v *FindTagsFindTagsFindTagsResultTypeTagsTag) GetAliases() []string { return v.TagParts.Aliases }
+
+// GetParents returns FindTagsFindTagsFindTagsResultTypeTagsTag.Parents, and is useful for accessing the field via an interface.
+func (v *FindTagsFindTagsFindTagsResultTypeTagsTag) GetParents() []*TagPartsParentsTag {
+	return v.TagParts.Parents
+}
+
+func (v *FindTagsFindTagsFindTagsResultTypeTagsTag) UnmarshalJSON(b []byte) error {
+
+	if string(b) == "null" {
+		return nil
+	}
+
+	var firstPass struct {
+		*FindTagsFindTagsFindTagsResultTypeTagsTag
+		graphql.NoUnmarshalJSON
+	}
+	firstPass.FindTagsFindTagsFindTagsResultTypeTagsTag = v
+
+	err := json.Unmarshal(b, &firstPass)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(
+		b, &v.TagParts)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+type __premarshalFindTagsFindTagsFindTagsResultTypeTagsTag struct {
+	Scene_count int `json:"scene_count"`
+
+	Id string `json:"id"`
+
+	Name string `json:"name"`
+
+	Sort_name string `json:"sort_name"`
+
+	Aliases []string `json:"aliases"`
+
+	Parents []*TagPartsParentsTag `json:"parents"`
+}
+
+func (v *FindTagsFindTagsFindTagsResultTypeTagsTag) MarshalJSON() ([]byte, error) {
+	premarshaled, err := v.__premarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(premarshaled)
+}
+
+func (v *FindTagsFindTagsFindTagsResultTypeTagsTag) __premarshalJSON() (*__premarshalFindTagsFindTagsFindTagsResultTypeTagsTag, error) {
+	var retval __premarshalFindTagsFindTagsFindTagsResultTypeTagsTag
+
+	retval.Scene_count = v.Scene_count
+	retval.Id = v.TagParts.Id
+	retval.Name = v.TagParts.Name
+	retval.Sort_name = v.TagParts.Sort_name
 	retval.Aliases = v.TagParts.Aliases
 	retval.Parents = v.TagParts.Parents
 	return &retval, nil
@@ -2048,6 +2580,24 @@ var AllOrientationEnum = []OrientationEnum{
 	OrientationEnumSquare,
 }
 
+// PerformerCreatePerformerCreatePerformer includes the requested fields of the GraphQL type Performer.
+type PerformerCreatePerformerCreatePerformer struct {
+	Id string `json:"id"`
+}
+
+// GetId returns PerformerCreatePerformerCreatePerformer.Id, and is useful for accessing the field via an interface.
+func (v *PerformerCreatePerformerCreatePerformer) GetId() string { return v.Id }
+
+// PerformerCreateResponse is returned by PerformerCreate on success.
+type PerformerCreateResponse struct {
+	PerformerCreate *PerformerCreatePerformerCreatePerformer `json:"performerCreate"`
+}
+
+// GetPerformerCreate returns PerformerCreateResponse.PerformerCreate, and is useful for accessing the field via an interface.
+func (v *PerformerCreateResponse) GetPerformerCreate() *PerformerCreatePerformerCreatePerformer {
+	return v.PerformerCreate
+}
+
 type PerformerFilterType struct {
 	AND *PerformerFilterType `json:"AND,omitempty"`
 	NOT *PerformerFilterType `json:"NOT,omitempty"`
@@ -2445,44 +2995,6 @@ type SceneAddPlayDurationSecondsResponse struct {
 // GetSceneSaveActivity returns SceneAddPlayDurationSecondsResponse.SceneSaveActivity, and is useful for accessing the field via an interface.
 func (v *SceneAddPlayDurationSecondsResponse) GetSceneSaveActivity() bool { return v.SceneSaveActivity }
 
-// SceneDecrementOResponse is returned by SceneDecrementO on success.
-type SceneDecrementOResponse struct {
-	// Decrements the o-counter for a scene, removing the last recorded time if specific time not provided. Returns the new value
-	SceneDeleteO *SceneDecrementOSceneDeleteOHistoryMutationResult `json:"sceneDeleteO"`
-}
-
-// GetSceneDeleteO returns SceneDecrementOResponse.SceneDeleteO, and is useful for accessing the field via an interface.
-func (v *SceneDecrementOResponse) GetSceneDeleteO() *SceneDecrementOSceneDeleteOHistoryMutationResult {
-	return v.SceneDeleteO
-}
-
-// SceneDecrementOSceneDeleteOHistoryMutationResult includes the requested fields of the GraphQL type HistoryMutationResult.
-type SceneDecrementOSceneDeleteOHistoryMutationResult struct {
-	Count int `json:"count"`
-}
-
-// GetCount returns SceneDecrementOSceneDeleteOHistoryMutationResult.Count, and is useful for accessing the field via an interface.
-func (v *SceneDecrementOSceneDeleteOHistoryMutationResult) GetCount() int { return v.Count }
-
-// SceneDecrementPlayCountResponse is returned by SceneDecrementPlayCount on success.
-type SceneDecrementPlayCountResponse struct {
-	// Decrements the play count for the scene, removing the specific times or the last recorded time if not provided.
-	SceneDeletePlay *SceneDecrementPlayCountSceneDeletePlayHistoryMutationResult `json:"sceneDeletePlay"`
-}
-
-// GetSceneDeletePlay returns SceneDecrementPlayCountResponse.SceneDeletePlay, and is useful for accessing the field via an interface.
-func (v *SceneDecrementPlayCountResponse) GetSceneDeletePlay() *SceneDecrementPlayCountSceneDeletePlayHistoryMutationResult {
-	return v.SceneDeletePlay
-}
-
-// SceneDecrementPlayCountSceneDeletePlayHistoryMutationResult includes the requested fields of the GraphQL type HistoryMutationResult.
-type SceneDecrementPlayCountSceneDeletePlayHistoryMutationResult struct {
-	Count int `json:"count"`
-}
-
-// GetCount returns SceneDecrementPlayCountSceneDeletePlayHistoryMutationResult.Count, and is useful for accessing the field via an interface.
-func (v *SceneDecrementPlayCountSceneDeletePlayHistoryMutationResult) GetCount() int { return v.Count }
-
 // SceneDestroyResponse is returned by SceneDestroy on success.
 type SceneDestroyResponse struct {
 	SceneDestroy bool `json:"sceneDestroy"`
@@ -2793,44 +3305,6 @@ func (v *SceneFilterType) GetUrl() *StringCriterionInput { return v.Url }
 // GetVideo_codec returns SceneFilterType.Video_codec, and is useful for accessing the field via an interface.
 func (v *SceneFilterType) GetVideo_codec() *StringCriterionInput { return v.Video_codec }
 
-// SceneIncrementOResponse is returned by SceneIncrementO on success.
-type SceneIncrementOResponse struct {
-	// Increments the o-counter for a scene. Uses the current time if none provided.
-	SceneAddO *SceneIncrementOSceneAddOHistoryMutationResult `json:"sceneAddO"`
-}
-
-// GetSceneAddO returns SceneIncrementOResponse.SceneAddO, and is useful for accessing the field via an interface.
-func (v *SceneIncrementOResponse) GetSceneAddO() *SceneIncrementOSceneAddOHistoryMutationResult {
-	return v.SceneAddO
-}
-
-// SceneIncrementOSceneAddOHistoryMutationResult includes the requested fields of the GraphQL type HistoryMutationResult.
-type SceneIncrementOSceneAddOHistoryMutationResult struct {
-	Count int `json:"count"`
-}
-
-// GetCount returns SceneIncrementOSceneAddOHistoryMutationResult.Count, and is useful for accessing the field via an interface.
-func (v *SceneIncrementOSceneAddOHistoryMutationResult) GetCount() int { return v.Count }
-
-// SceneIncrementPlayCountResponse is returned by SceneIncrementPlayCount on success.
-type SceneIncrementPlayCountResponse struct {
-	// Increments the play count for the scene. Uses the current time if none provided.
-	SceneAddPlay *SceneIncrementPlayCountSceneAddPlayHistoryMutationResult `json:"sceneAddPlay"`
-}
-
-// GetSceneAddPlay returns SceneIncrementPlayCountResponse.SceneAddPlay, and is useful for accessing the field via an interface.
-func (v *SceneIncrementPlayCountResponse) GetSceneAddPlay() *SceneIncrementPlayCountSceneAddPlayHistoryMutationResult {
-	return v.SceneAddPlay
-}
-
-// SceneIncrementPlayCountSceneAddPlayHistoryMutationResult includes the requested fields of the GraphQL type HistoryMutationResult.
-type SceneIncrementPlayCountSceneAddPlayHistoryMutationResult struct {
-	Count int `json:"count"`
-}
-
-// GetCount returns SceneIncrementPlayCountSceneAddPlayHistoryMutationResult.Count, and is useful for accessing the field via an interface.
-func (v *SceneIncrementPlayCountSceneAddPlayHistoryMutationResult) GetCount() int { return v.Count }
-
 // SceneMarkerCreateResponse is returned by SceneMarkerCreate on success.
 type SceneMarkerCreateResponse struct {
 	SceneMarkerCreate *SceneMarkerCreateSceneMarkerCreateSceneMarker `json:"sceneMarkerCreate"`
@@ -2949,24 +3423,6 @@ func (v *SceneMarkerPartsPrimary_tagTag) GetId() string { return v.Id }
 // GetName returns SceneMarkerPartsPrimary_tagTag.Name, and is useful for accessing the field via an interface.
 func (v *SceneMarkerPartsPrimary_tagTag) GetName() string { return v.Name }
 
-// SceneMarkerUpdateResponse is returned by SceneMarkerUpdate on success.
-type SceneMarkerUpdateResponse struct {
-	SceneMarkerUpdate *SceneMarkerUpdateSceneMarkerUpdateSceneMarker `json:"sceneMarkerUpdate"`
-}
-
-// GetSceneMarkerUpdate returns SceneMarkerUpdateResponse.SceneMarkerUpdate, and is useful for accessing the field via an interface.
-func (v *SceneMarkerUpdateResponse) GetSceneMarkerUpdate() *SceneMarkerUpdateSceneMarkerUpdateSceneMarker {
-	return v.SceneMarkerUpdate
-}
-
-// SceneMarkerUpdateSceneMarkerUpdateSceneMarker includes the requested fields of the GraphQL type SceneMarker.
-type SceneMarkerUpdateSceneMarkerUpdateSceneMarker struct {
-	Id string `json:"id"`
-}
-
-// GetId returns SceneMarkerUpdateSceneMarkerUpdateSceneMarker.Id, and is useful for accessing the field via an interface.
-func (v *SceneMarkerUpdateSceneMarkerUpdateSceneMarker) GetId() string { return v.Id }
-
 // SceneMarkersDestroyResponse is returned by SceneMarkersDestroy on success.
 type SceneMarkersDestroyResponse struct {
 	SceneMarkersDestroy bool `json:"sceneMarkersDestroy"`
@@ -2979,19 +3435,23 @@ func (v *SceneMarkersDestroyResponse) GetSceneMarkersDestroy() bool { return v.S
 type SceneParts struct {
 	Id            string                                `json:"id"`
 	Title         *string                               `json:"title"`
+	Code          *string                               `json:"code"`
+	Details       *string                               `json:"details"`
 	Rating100     *int                                  `json:"rating100"`
 	Created_at    time.Time                             `json:"created_at"`
+	Updated_at    time.Time                             `json:"updated_at"`
 	Date          *string                               `json:"date"`
 	Files         []*ScenePartsFilesVideoFile           `json:"files"`
 	Studio        *ScenePartsStudio                     `json:"studio"`
 	Scene_markers []*ScenePartsScene_markersSceneMarker `json:"scene_markers"`
 	Performers    []*ScenePartsPerformersPerformer      `json:"performers"`
-	Groups        []*ScenePartsGroupsSceneGroup         `json:"groups"`
 	// The number ot times a scene has been played
-	Play_count *int                           `json:"play_count"`
-	O_counter  *int                           `json:"o_counter"`
-	Organized  bool                           `json:"organized"`
-	Paths      *ScenePartsPathsScenePathsType `json:"paths"`
+	Play_count *int `json:"play_count"`
+	// The time index a scene was left at
+	Resume_time *float64                       `json:"resume_time"`
+	O_counter   *int                           `json:"o_counter"`
+	Organized   bool                           `json:"organized"`
+	Paths       *ScenePartsPathsScenePathsType `json:"paths"`
 	// Return valid stream paths
 	SceneStreams  []*ScenePartsSceneStreamsSceneStreamEndpoint `json:"sceneStreams"`
 	Captions      []*ScenePartsCaptionsVideoCaption            `json:"captions"`
@@ -3005,12 +3465,21 @@ func (v *SceneParts) GetId() string { return v.Id }
 // GetTitle returns SceneParts.Title, and is useful for accessing the field via an interface.
 func (v *SceneParts) GetTitle() *string { return v.Title }
 
+// GetCode returns SceneParts.Code, and is useful for accessing the field via an interface.
+func (v *SceneParts) GetCode() *string { return v.Code }
+
+// GetDetails returns SceneParts.Details, and is useful for accessing the field via an interface.
+func (v *SceneParts) GetDetails() *string { return v.Details }
+
 // GetRating100 returns SceneParts.Rating100, and is useful for accessing the field via an interface.
 func (v *SceneParts) GetRating100() *int { return v.Rating100 }
 
 // GetCreated_at returns SceneParts.Created_at, and is useful for accessing the field via an interface.
 func (v *SceneParts) GetCreated_at() time.Time { return v.Created_at }
 
+// GetUpdated_at returns SceneParts.Updated_at, and is useful for accessing the field via an interface.
+func (v *SceneParts) GetUpdated_at() time.Time { return v.Updated_at }
+
 // GetDate returns SceneParts.Date, and is useful for accessing the field via an interface.
 func (v *SceneParts) GetDate() *string { return v.Date }
 
@@ -3026,12 +3495,12 @@ func (v *SceneParts) GetScene_markers() []*ScenePartsScene_markersSceneMarker {
 // GetPerformers returns SceneParts.Performers, and is useful for accessing the field via an interface.
 func (v *SceneParts) GetPerformers() []*ScenePartsPerformersPerformer { return v.Performers }
 
-// GetGroups returns SceneParts.Groups, and is useful for accessing the field via an interface.
-func (v *SceneParts) GetGroups() []*ScenePartsGroupsSceneGroup { return v.Groups }
-
 // GetPlay_count returns SceneParts.Play_count, and is useful for accessing the field via an interface.
 func (v *SceneParts) GetPlay_count() *int { return v.Play_count }
 
+// GetResume_time returns SceneParts.Resume_time, and is useful for accessing the field via an interface.
+func (v *SceneParts) GetResume_time() *float64 { return v.Resume_time }
+
 // GetO_counter returns SceneParts.O_counter, and is useful for accessing the field via an interface.
 func (v *SceneParts) GetO_counter() *int { return v.O_counter }
 
@@ -3085,10 +3554,16 @@ type __premarshalSceneParts struct {
 
 	Title *string `json:"title"`
 
+	Code *string `json:"code"`
+
+	Details *string `json:"details"`
+
 	Rating100 *int `json:"rating100"`
 
 	Created_at time.Time `json:"created_at"`
 
+	Updated_at time.Time `json:"updated_at"`
+
 	Date *string `json:"date"`
 
 	Files []*ScenePartsFilesVideoFile `json:"files"`
@@ -3099,10 +3574,10 @@ type __premarshalSceneParts struct {
 
 	Performers []*ScenePartsPerformersPerformer `json:"performers"`
 
-	Groups []*ScenePartsGroupsSceneGroup `json:"groups"`
-
 	Play_count *int `json:"play_count"`
 
+	Resume_time *float64 `json:"resume_time"`
+
 	O_counter *int `json:"o_counter"`
 
 	Organized bool `json:"organized"`
@@ -3131,15 +3606,18 @@ func (v *SceneParts) __premarshalJSON() (*__premarshalSceneParts, error) {
 
 	retval.Id = v.Id
 	retval.Title = v.Title
+	retval.Code = v.Code
+	retval.Details = v.Details
 	retval.Rating100 = v.Rating100
 	retval.Created_at = v.Created_at
+	retval.Updated_at = v.Updated_at
 	retval.Date = v.Date
 	retval.Files = v.Files
 	retval.Studio = v.Studio
 	retval.Scene_markers = v.Scene_markers
 	retval.Performers = v.Performers
-	retval.Groups = v.Groups
 	retval.Play_count = v.Play_count
+	retval.Resume_time = v.Resume_time
 	retval.O_counter = v.O_counter
 	retval.Organized = v.Organized
 	retval.Paths = v.Paths
@@ -3186,22 +3664,6 @@ func (v *ScenePartsFilesVideoFile) GetHeight() int { return v.Height }
 // GetVideo_codec returns ScenePartsFilesVideoFile.Video_codec, and is useful for accessing the field via an interface.
 func (v *ScenePartsFilesVideoFile) GetVideo_codec() string { return v.Video_codec }
 
-// ScenePartsGroupsSceneGroup includes the requested fields of the GraphQL type SceneGroup.
-type ScenePartsGroupsSceneGroup struct {
-	Group *ScenePartsGroupsSceneGroupGroup `json:"group"`
-}
-
-// GetGroup returns ScenePartsGroupsSceneGroup.Group, and is useful for accessing the field via an interface.
-func (v *ScenePartsGroupsSceneGroup) GetGroup() *ScenePartsGroupsSceneGroupGroup { return v.Group }
-
-// ScenePartsGroupsSceneGroupGroup includes the requested fields of the GraphQL type Group.
-type ScenePartsGroupsSceneGroupGroup struct {
-	Name string `json:"name"`
-}
-
-// GetName returns ScenePartsGroupsSceneGroupGroup.Name, and is useful for accessing the field via an interface.
-func (v *ScenePartsGroupsSceneGroupGroup) GetName() string { return v.Name }
-
 // ScenePartsPathsScenePathsType includes the requested fields of the GraphQL type ScenePathsType.
 type ScenePartsPathsScenePathsType struct {
 	Screenshot          *string `json:"screenshot"`
@@ -3234,9 +3696,13 @@ func (v *ScenePartsPathsScenePathsType) GetCaption() *string { return v.Caption
 
 // ScenePartsPerformersPerformer includes the requested fields of the GraphQL type Performer.
 type ScenePartsPerformersPerformer struct {
+	Id   string `json:"id"`
 	Name string `json:"name"`
 }
 
+// GetId returns ScenePartsPerformersPerformer.Id, and is useful for accessing the field via an interface.
+func (v *ScenePartsPerformersPerformer) GetId() string { return v.Id }
+
 // GetName returns ScenePartsPerformersPerformer.Name, and is useful for accessing the field via an interface.
 func (v *ScenePartsPerformersPerformer) GetName() string { return v.Name }
 
@@ -3338,69 +3804,147 @@ func (v *ScenePartsScene_markersSceneMarker) __premarshalJSON() (*__premarshalSc
 
 // ScenePartsStudio includes the requested fields of the GraphQL type Studio.
 type ScenePartsStudio struct {
+	Id   string `json:"id"`
 	Name string `json:"name"`
 }
 
+// GetId returns ScenePartsStudio.Id, and is useful for accessing the field via an interface.
+func (v *ScenePartsStudio) GetId() string { return v.Id }
+
 // GetName returns ScenePartsStudio.Name, and is useful for accessing the field via an interface.
 func (v *ScenePartsStudio) GetName() string { return v.Name }
 
-// SceneUpdateOrganizedResponse is returned by SceneUpdateOrganized on success.
-type SceneUpdateOrganizedResponse struct {
-	SceneUpdate *SceneUpdateOrganizedSceneUpdateScene `json:"sceneUpdate"`
+// SceneSaveResumeTimeResponse is returned by SceneSaveResumeTime on success.
+type SceneSaveResumeTimeResponse struct {
+	// Sets the resume time point (if provided) and adds the provided duration to the scene's play duration
+	SceneSaveActivity bool `json:"sceneSaveActivity"`
 }
 
-// GetSceneUpdate returns SceneUpdateOrganizedResponse.SceneUpdate, and is useful for accessing the field via an interface.
-func (v *SceneUpdateOrganizedResponse) GetSceneUpdate() *SceneUpdateOrganizedSceneUpdateScene {
-	return v.SceneUpdate
+// GetSceneSaveActivity returns SceneSaveResumeTimeResponse.SceneSaveActivity, and is useful for accessing the field via an interface.
+func (v *SceneSaveResumeTimeResponse) GetSceneSaveActivity() bool { return v.SceneSaveActivity }
+
+type SceneUpdateInput struct {
+	ClientMutationId *string  `json:"clientMutationId,omitempty"`
+	Code             **string `json:"code,omitempty"`
+	// This should be a URL or a base64 encoded data URL
+	Cover_image   *string             `json:"cover_image,omitempty"`
+	Custom_fields *CustomFieldsInput  `json:"custom_fields,omitempty"`
+	Date          **string            `json:"date,omitempty"`
+	Details       **string            `json:"details,omitempty"`
+	Director      *string             `json:"director,omitempty"`
+	Gallery_ids   []string            `json:"gallery_ids,omitempty"`
+	Groups        *[]*SceneGroupInput `json:"groups,omitempty"`
+	Id            string              `json:"id,omitempty"`
+	Movies        *[]*SceneMovieInput `json:"movies,omitempty"`
+	O_counter     *int                `json:"o_counter,omitempty"`
+	Organized     *bool               `json:"organized,omitempty"`
+	Performer_ids *[]string           `json:"performer_ids,omitempty"`
+	// The number ot times a scene has been played
+	Play_count *int `json:"play_count,omitempty"`
+	// The total time a scene has spent playing
+	Play_duration   *float64 `json:"play_duration,omitempty"`
+	Primary_file_id *string  `json:"primary_file_id,omitempty"`
+	Rating100       **int    `json:"rating100,omitempty"`
+	// The time index a scene was left at
+	Resume_time *float64        `json:"resume_time,omitempty"`
+	Stash_ids   []*StashIDInput `json:"stash_ids,omitempty"`
+	Studio_id   **string        `json:"studio_id,omitempty"`
+	Tag_ids     *[]string       `json:"tag_ids,omitempty"`
+	Title       **string        `json:"title,omitempty"`
+	Url         *string         `json:"url,omitempty"`
+	Urls        []string        `json:"urls,omitempty"`
 }
 
-// SceneUpdateOrganizedSceneUpdateScene includes the requested fields of the GraphQL type Scene.
-type SceneUpdateOrganizedSceneUpdateScene struct {
-	Id        string `json:"id"`
-	Organized bool   `json:"organized"`
-}
+// GetClientMutationId returns SceneUpdateInput.ClientMutationId, and is useful for accessing the field via an interface.
+func (v *SceneUpdateInput) GetClientMutationId() *string { return v.ClientMutationId }
 
-// GetId returns SceneUpdateOrganizedSceneUpdateScene.Id, and is useful for accessing the field via an interface.
-func (v *SceneUpdateOrganizedSceneUpdateScene) GetId() string { return v.Id }
+// GetCode returns SceneUpdateInput.Code, and is useful for accessing the field via an interface.
+func (v *SceneUpdateInput) GetCode() **string { return v.Code }
 
-// GetOrganized returns SceneUpdateOrganizedSceneUpdateScene.Organized, and is useful for accessing the field via an interface.
-func (v *SceneUpdateOrganizedSceneUpdateScene) GetOrganized() bool { return v.Organized }
+// GetCover_image returns SceneUpdateInput.Cover_image, and is useful for accessing the field via an interface.
+func (v *SceneUpdateInput) GetCover_image() *string { return v.Cover_image }
 
-// SceneUpdateRating100Response is returned by SceneUpdateRating100 on success.
-type SceneUpdateRating100Response struct {
-	SceneUpdate *SceneUpdateRating100SceneUpdateScene `json:"sceneUpdate"`
-}
+// GetCustom_fields returns SceneUpdateInput.Custom_fields, and is useful for accessing the field via an interface.
+func (v *SceneUpdateInput) GetCustom_fields() *CustomFieldsInput { return v.Custom_fields }
 
-// GetSceneUpdate returns SceneUpdateRating100Response.SceneUpdate, and is useful for accessing the field via an interface.
-func (v *SceneUpdateRating100Response) GetSceneUpdate() *SceneUpdateRating100SceneUpdateScene {
-	return v.SceneUpdate
-}
+// GetDate returns SceneUpdateInput.Date, and is useful for accessing the field via an interface.
+func (v *SceneUpdateInput) GetDate() **string { return v.Date }
 
-// SceneUpdateRating100SceneUpdateScene includes the requested fields of the GraphQL type Scene.
-type SceneUpdateRating100SceneUpdateScene struct {
-	Id string `json:"id"`
-}
+// GetDetails returns SceneUpdateInput.Details, and is useful for accessing the field via an interface.
+func (v *SceneUpdateInput) GetDetails() **string { return v.Details }
 
-// GetId returns SceneUpdateRating100SceneUpdateScene.Id, and is useful for accessing the field via an interface.
-func (v *SceneUpdateRating100SceneUpdateScene) GetId() string { return v.Id }
+// GetDirector returns SceneUpdateInput.Director, and is useful for accessing the field via an interface.
+func (v *SceneUpdateInput) GetDirector() *string { return v.Director }
 
-// SceneUpdateTagsResponse is returned by SceneUpdateTags on success.
-type SceneUpdateTagsResponse struct {
-	SceneUpdate *SceneUpdateTagsSceneUpdateScene `json:"sceneUpdate"`
-}
+// GetGallery_ids returns SceneUpdateInput.Gallery_ids, and is useful for accessing the field via an interface.
+func (v *SceneUpdateInput) GetGallery_ids() []string { return v.Gallery_ids }
+
+// GetGroups returns SceneUpdateInput.Groups, and is useful for accessing the field via an interface.
+func (v *SceneUpdateInput) GetGroups() *[]*SceneGroupInput { return v.Groups }
+
+// GetId returns SceneUpdateInput.Id, and is useful for accessing the field via an interface.
+func (v *SceneUpdateInput) GetId() string { return v.Id }
+
+// GetMovies returns SceneUpdateInput.Movies, and is useful for accessing the field via an interface.
+func (v *SceneUpdateInput) GetMovies() *[]*SceneMovieInput { return v.Movies }
+
+// GetO_counter returns SceneUpdateInput.O_counter, and is useful for accessing the field via an interface.
+func (v *SceneUpdateInput) GetO_counter() *int { return v.O_counter }
+
+// GetOrganized returns SceneUpdateInput.Organized, and is useful for accessing the field via an interface.
+func (v *SceneUpdateInput) GetOrganized() *bool { return v.Organized }
+
+// GetPerformer_ids returns SceneUpdateInput.Performer_ids, and is useful for accessing the field via an interface.
+func (v *SceneUpdateInput) GetPerformer_ids() *[]string { return v.Performer_ids }
+
+// GetPlay_count returns SceneUpdateInput.Play_count, and is useful for accessing the field via an interface.
+func (v *SceneUpdateInput) GetPlay_count() *int { return v.Play_count }
+
+// GetPlay_duration returns SceneUpdateInput.Play_duration, and is useful for accessing the field via an interface.
+func (v *SceneUpdateInput) GetPlay_duration() *float64 { return v.Play_duration }
+
+// GetPrimary_file_id returns SceneUpdateInput.Primary_file_id, and is useful for accessing the field via an interface.
+func (v *SceneUpdateInput) GetPrimary_file_id() *string { return v.Primary_file_id }
+
+// GetRating100 returns SceneUpdateInput.Rating100, and is useful for accessing the field via an interface.
+func (v *SceneUpdateInput) GetRating100() **int { return v.Rating100 }
+
+// GetResume_time returns SceneUpdateInput.Resume_time, and is useful for accessing the field via an interface.
+func (v *SceneUpdateInput) GetResume_time() *float64 { return v.Resume_time }
+
+// GetStash_ids returns SceneUpdateInput.Stash_ids, and is useful for accessing the field via an interface.
+func (v *SceneUpdateInput) GetStash_ids() []*StashIDInput { return v.Stash_ids }
+
+// GetStudio_id returns SceneUpdateInput.Studio_id, and is useful for accessing the field via an interface.
+func (v *SceneUpdateInput) GetStudio_id() **string { return v.Studio_id }
+
+// GetTag_ids returns SceneUpdateInput.Tag_ids, and is useful for accessing the field via an interface.
+func (v *SceneUpdateInput) GetTag_ids() *[]string { return v.Tag_ids }
+
+// GetTitle returns SceneUpdateInput.Title, and is useful for accessing the field via an interface.
+func (v *SceneUpdateInput) GetTitle() **string { return v.Title }
+
+// GetUrl returns SceneUpdateInput.Url, and is useful for accessing the field via an interface.
+func (v *SceneUpdateInput) GetUrl() *string { return v.Url }
 
-// GetSceneUpdate returns SceneUpdateTagsResponse.SceneUpdate, and is useful for accessing the field via an interface.
-func (v *SceneUpdateTagsResponse) GetSceneUpdate() *SceneUpdateTagsSceneUpdateScene {
-	return v.SceneUpdate
+// GetUrls returns SceneUpdateInput.Urls, and is useful for accessing the field via an interface.
+func (v *SceneUpdateInput) GetUrls() []string { return v.Urls }
+
+// SceneUpdateResponse is returned by SceneUpdate on success.
+type SceneUpdateResponse struct {
+	SceneUpdate *SceneUpdateSceneUpdateScene `json:"sceneUpdate"`
 }
 
-// SceneUpdateTagsSceneUpdateScene includes the requested fields of the GraphQL type Scene.
-type SceneUpdateTagsSceneUpdateScene struct {
+// GetSceneUpdate returns SceneUpdateResponse.SceneUpdate, and is useful for accessing the field via an interface.
+func (v *SceneUpdateResponse) GetSceneUpdate() *SceneUpdateSceneUpdateScene { return v.SceneUpdate }
+
+// SceneUpdateSceneUpdateScene includes the requested fields of the GraphQL type Scene.
+type SceneUpdateSceneUpdateScene struct {
 	Id string `json:"id"`
 }
 
-// GetId returns SceneUpdateTagsSceneUpdateScene.Id, and is useful for accessing the field via an interface.
-func (v *SceneUpdateTagsSceneUpdateScene) GetId() string { return v.Id }
+// GetId returns SceneUpdateSceneUpdateScene.Id, and is useful for accessing the field via an interface.
+func (v *SceneUpdateSceneUpdateScene) GetId() string { return v.Id }
 
 type SortDirectionEnum string
 
@@ -3431,6 +3975,21 @@ func (v *StashIDCriterionInput) GetModifier() CriterionModifier { return v.Modif
 // GetStash_id returns StashIDCriterionInput.Stash_id, and is useful for accessing the field via an interface.
 func (v *StashIDCriterionInput) GetStash_id() *string { return v.Stash_id }
 
+type StashIDInput struct {
+	Endpoint   string     `json:"endpoint,omitempty"`
+	Stash_id   string     `json:"stash_id,omitempty"`
+	Updated_at *time.Time `json:"updated_at,omitempty"`
+}
+
+// GetEndpoint returns StashIDInput.Endpoint, and is useful for accessing the field via an interface.
+func (v *StashIDInput) GetEndpoint() string { return v.Endpoint }
+
+// GetStash_id returns StashIDInput.Stash_id, and is useful for accessing the field via an interface.
+func (v *StashIDInput) GetStash_id() string { return v.Stash_id }
+
+// GetUpdated_at returns StashIDInput.Updated_at, and is useful for accessing the field via an interface.
+func (v *StashIDInput) GetUpdated_at() *time.Time { return v.Updated_at }
+
 type StashIDsCriterionInput struct {
 	// If present, this value is treated as a predicate.
 	// That is, it will filter based on stash_ids with the matching endpoint
@@ -4014,6 +4573,22 @@ func (v *VideoFileFilterInput) GetResolution() *ResolutionCriterionInput { retur
 // GetVideo_codec returns VideoFileFilterInput.Video_codec, and is useful for accessing the field via an interface.
 func (v *VideoFileFilterInput) GetVideo_codec() *StringCriterionInput { return v.Video_codec }
 
+// __FindGroupsByNameInput is used internally by genqlient
+type __FindGroupsByNameInput struct {
+	Name string `json:"name"`
+}
+
+// GetName returns __FindGroupsByNameInput.Name, and is useful for accessing the field via an interface.
+func (v *__FindGroupsByNameInput) GetName() string { return v.Name }
+
+// __FindMoviesByNameInput is used internally by genqlient
+type __FindMoviesByNameInput struct {
+	Name string `json:"name"`
+}
+
+// GetName returns __FindMoviesByNameInput.Name, and is useful for accessing the field via an interface.
+func (v *__FindMoviesByNameInput) GetName() string { return v.Name }
+
 // __FindPerformerByNameInput is used internally by genqlient
 type __FindPerformerByNameInput struct {
 	Name string `json:"name"`
@@ -4022,6 +4597,14 @@ type __FindPerformerByNameInput struct {
 // GetName returns __FindPerformerByNameInput.Name, and is useful for accessing the field via an interface.
 func (v *__FindPerformerByNameInput) GetName() string { return v.Name }
 
+// __FindPerformersByNameInput is used internally by genqlient
+type __FindPerformersByNameInput struct {
+	Name string `json:"name"`
+}
+
+// GetName returns __FindPerformersByNameInput.Name, and is useful for accessing the field via an interface.
+func (v *__FindPerformersByNameInput) GetName() string { return v.Name }
+
 // __FindSceneIdsByFilterInput is used internally by genqlient
 type __FindSceneIdsByFilterInput struct {
 	Scene_filter *SceneFilterType `json:"scene_filter,omitempty"`
@@ -4050,13 +4633,21 @@ type __FindSceneTagsInput struct {
 // GetScene_id returns __FindSceneTagsInput.Scene_id, and is useful for accessing the field via an interface.
 func (v *__FindSceneTagsInput) GetScene_id() string { return v.Scene_id }
 
-// __FindScenesInput is used internally by genqlient
-type __FindScenesInput struct {
+// __FindScenesWithGroupsInput is used internally by genqlient
+type __FindScenesWithGroupsInput struct {
+	Scene_ids []int `json:"scene_ids"`
+}
+
+// GetScene_ids returns __FindScenesWithGroupsInput.Scene_ids, and is useful for accessing the field via an interface.
+func (v *__FindScenesWithGroupsInput) GetScene_ids() []int { return v.Scene_ids }
+
+// __FindScenesWithMoviesInput is used internally by genqlient
+type __FindScenesWithMoviesInput struct {
 	Scene_ids []int `json:"scene_ids"`
 }
 
-// GetScene_ids returns __FindScenesInput.Scene_ids, and is useful for accessing the field via an interface.
-func (v *__FindScenesInput) GetScene_ids() []int { return v.Scene_ids }
+// GetScene_ids returns __FindScenesWithMoviesInput.Scene_ids, and is useful for accessing the field via an interface.
+func (v *__FindScenesWithMoviesInput) GetScene_ids() []int { return v.Scene_ids }
 
 // __FindStudioByNameInput is used internally by genqlient
 type __FindStudioByNameInput struct {
@@ -4098,6 +4689,14 @@ type __IsSceneOrganizedInput struct {
 // GetId returns __IsSceneOrganizedInput.Id, and is useful for accessing the field via an interface.
 func (v *__IsSceneOrganizedInput) GetId() *string { return v.Id }
 
+// __PerformerCreateInput is used internally by genqlient
+type __PerformerCreateInput struct {
+	Name string `json:"name"`
+}
+
+// GetName returns __PerformerCreateInput.Name, and is useful for accessing the field via an interface.
+func (v *__PerformerCreateInput) GetName() string { return v.Name }
+
 // __SceneAddPlayDurationSecondsInput is used internally by genqlient
 type __SceneAddPlayDurationSecondsInput struct {
 	Id      string   `json:"id"`
@@ -4110,22 +4709,6 @@ func (v *__SceneAddPlayDurationSecondsInput) GetId() string { return v.Id }
 // GetSeconds returns __SceneAddPlayDurationSecondsInput.Seconds, and is useful for accessing the field via an interface.
 func (v *__SceneAddPlayDurationSecondsInput) GetSeconds() *float64 { return v.Seconds }
 
-// __SceneDecrementOInput is used internally by genqlient
-type __SceneDecrementOInput struct {
-	Id string `json:"id"`
-}
-
-// GetId returns __SceneDecrementOInput.Id, and is useful for accessing the field via an interface.
-func (v *__SceneDecrementOInput) GetId() string { return v.Id }
-
-// __SceneDecrementPlayCountInput is used internally by genqlient
-type __SceneDecrementPlayCountInput struct {
-	Id string `json:"id"`
-}
-
-// GetId returns __SceneDecrementPlayCountInput.Id, and is useful for accessing the field via an interface.
-func (v *__SceneDecrementPlayCountInput) GetId() string { return v.Id }
-
 // __SceneDestroyInput is used internally by genqlient
 type __SceneDestroyInput struct {
 	Id string `json:"id"`
@@ -4134,22 +4717,6 @@ type __SceneDestroyInput struct {
 // GetId returns __SceneDestroyInput.Id, and is useful for accessing the field via an interface.
 func (v *__SceneDestroyInput) GetId() string { return v.Id }
 
-// __SceneIncrementOInput is used internally by genqlient
-type __SceneIncrementOInput struct {
-	Id string `json:"id"`
-}
-
-// GetId returns __SceneIncrementOInput.Id, and is useful for accessing the field via an interface.
-func (v *__SceneIncrementOInput) GetId() string { return v.Id }
-
-// __SceneIncrementPlayCountInput is used internally by genqlient
-type __SceneIncrementPlayCountInput struct {
-	Id string `json:"id"`
-}
-
-// GetId returns __SceneIncrementPlayCountInput.Id, and is useful for accessing the field via an interface.
-func (v *__SceneIncrementPlayCountInput) GetId() string { return v.Id }
-
 // __SceneMarkerCreateInput is used internally by genqlient
 type __SceneMarkerCreateInput struct {
 	Scene_id    string   `json:"scene_id"`
@@ -4174,30 +4741,6 @@ func (v *__SceneMarkerCreateInput) GetEnd_seconds() *float64 { return v.End_seco
 // GetTitle returns __SceneMarkerCreateInput.Title, and is useful for accessing the field via an interface.
 func (v *__SceneMarkerCreateInput) GetTitle() string { return v.Title }
 
-// __SceneMarkerUpdateInput is used internally by genqlient
-type __SceneMarkerUpdateInput struct {
-	Id          string   `json:"id"`
-	Tag_id      string   `json:"tag_id"`
-	Seconds     float64  `json:"seconds"`
-	End_seconds *float64 `json:"end_seconds"`
-	Title       string   `json:"title"`
-}
-
-// GetId returns __SceneMarkerUpdateInput.Id, and is useful for accessing the field via an interface.
-func (v *__SceneMarkerUpdateInput) GetId() string { return v.Id }
-
-// GetTag_id returns __SceneMarkerUpdateInput.Tag_id, and is useful for accessing the field via an interface.
-func (v *__SceneMarkerUpdateInput) GetTag_id() string { return v.Tag_id }
-
-// GetSeconds returns __SceneMarkerUpdateInput.Seconds, and is useful for accessing the field via an interface.
-func (v *__SceneMarkerUpdateInput) GetSeconds() float64 { return v.Seconds }
-
-// GetEnd_seconds returns __SceneMarkerUpdateInput.End_seconds, and is useful for accessing the field via an interface.
-func (v *__SceneMarkerUpdateInput) GetEnd_seconds() *float64 { return v.End_seconds }
-
-// GetTitle returns __SceneMarkerUpdateInput.Title, and is useful for accessing the field via an interface.
-func (v *__SceneMarkerUpdateInput) GetTitle() string { return v.Title }
-
 // __SceneMarkersDestroyInput is used internally by genqlient
 type __SceneMarkersDestroyInput struct {
 	Ids []string `json:"ids"`
@@ -4206,41 +4749,25 @@ type __SceneMarkersDestroyInput struct {
 // GetIds returns __SceneMarkersDestroyInput.Ids, and is useful for accessing the field via an interface.
 func (v *__SceneMarkersDestroyInput) GetIds() []string { return v.Ids }
 
-// __SceneUpdateOrganizedInput is used internally by genqlient
-type __SceneUpdateOrganizedInput struct {
-	Id          string `json:"id"`
-	IsOrganized *bool  `json:"isOrganized"`
-}
-
-// GetId returns __SceneUpdateOrganizedInput.Id, and is useful for accessing the field via an interface.
-func (v *__SceneUpdateOrganizedInput) GetId() string { return v.Id }
-
-// GetIsOrganized returns __SceneUpdateOrganizedInput.IsOrganized, and is useful for accessing the field via an interface.
-func (v *__SceneUpdateOrganizedInput) GetIsOrganized() *bool { return v.IsOrganized }
-
-// __SceneUpdateRating100Input is used internally by genqlient
-type __SceneUpdateRating100Input struct {
-	Id     string `json:"id"`
-	Rating *int   `json:"rating"`
+// __SceneSaveResumeTimeInput is used internally by genqlient
+type __SceneSaveResumeTimeInput struct {
+	Id          string   `json:"id"`
+	Resume_time *float64 `json:"resume_time"`
 }
 
-// GetId returns __SceneUpdateRating100Input.Id, and is useful for accessing the field via an interface.
-func (v *__SceneUpdateRating100Input) GetId() string { return v.Id }
+// GetId returns __SceneSaveResumeTimeInput.Id, and is useful for accessing the field via an interface.
+func (v *__SceneSaveResumeTimeInput) GetId() string { return v.Id }
 
-// GetRating returns __SceneUpdateRating100Input.Rating, and is useful for accessing the field via an interface.
-func (v *__SceneUpdateRating100Input) GetRating() *int { return v.Rating }
+// GetResume_time returns __SceneSaveResumeTimeInput.Resume_time, and is useful for accessing the field via an interface.
+func (v *__SceneSaveResumeTimeInput) GetResume_time() *float64 { return v.Resume_time }
 
-// __SceneUpdateTagsInput is used internally by genqlient
-type __SceneUpdateTagsInput struct {
-	Id      string   `json:"id"`
-	Tag_ids []string `json:"tag_ids"`
+// __SceneUpdateInput is used internally by genqlient
+type __SceneUpdateInput struct {
+	Input *SceneUpdateInput `json:"input,omitempty"`
 }
 
-// GetId returns __SceneUpdateTagsInput.Id, and is useful for accessing the field via an interface.
-func (v *__SceneUpdateTagsInput) GetId() string { return v.Id }
-
-// GetTag_ids returns __SceneUpdateTagsInput.Tag_ids, and is useful for accessing the field via an interface.
-func (v *__SceneUpdateTagsInput) GetTag_ids() []string { return v.Tag_ids }
+// GetInput returns __SceneUpdateInput.Input, and is useful for accessing the field via an interface.
+func (v *__SceneUpdateInput) GetInput() *SceneUpdateInput { return v.Input }
 
 // __TagCreateInput is used internally by genqlient
 type __TagCreateInput struct {
@@ -4250,6 +4777,43 @@ type __TagCreateInput struct {
 // GetName returns __TagCreateInput.Name, and is useful for accessing the field via an interface.
 func (v *__TagCreateInput) GetName() string { return v.Name }
 
+// The query executed by Capabilities.
+const Capabilities_Operation = `
+query Capabilities {
+	scene: __type(name: "Scene") {
+		fields {
+			name
+		}
+	}
+	mutation: __type(name: "Mutation") {
+		fields {
+			name
+		}
+	}
+}
+`
+
+func Capabilities(
+	ctx_ context.Context,
+	client_ graphql.Client,
+) (data_ *CapabilitiesResponse, err_ error) {
+	req_ := &graphql.Request{
+		OpName: "Capabilities",
+		Query:  Capabilities_Operation,
+	}
+
+	data_ = &CapabilitiesResponse{}
+	resp_ := &graphql.Response{Data: data_}
+
+	err_ = client_.MakeRequest(
+		ctx_,
+		req_,
+		resp_,
+	)
+
+	return data_, err_
+}
+
 // The query executed by FindAllSceneIds.
 const FindAllSceneIds_Operation = `
 query FindAllSceneIds {
@@ -4358,6 +4922,80 @@ func FindCredentials(
 	return data_, err_
 }
 
+// The query executed by FindGroupsByName.
+const FindGroupsByName_Operation = `
+query FindGroupsByName ($name: String!) {
+	findGroups(group_filter: {name:{value:$name,modifier:EQUALS}}) {
+		groups {
+			id
+			name
+		}
+	}
+}
+`
+
+func FindGroupsByName(
+	ctx_ context.Context,
+	client_ graphql.Client,
+	name string,
+) (data_ *FindGroupsByNameResponse, err_ error) {
+	req_ := &graphql.Request{
+		OpName: "FindGroupsByName",
+		Query:  FindGroupsByName_Operation,
+		Variables: &__FindGroupsByNameInput{
+			Name: name,
+		},
+	}
+
+	data_ = &FindGroupsByNameResponse{}
+	resp_ := &graphql.Response{Data: data_}
+
+	err_ = client_.MakeRequest(
+		ctx_,
+		req_,
+		resp_,
+	)
+
+	return data_, err_
+}
+
+// The query executed by FindMoviesByName.
+const FindMoviesByName_Operation = `
+query FindMoviesByName ($name: String!) {
+	findMovies(movie_filter: {name:{value:$name,modifier:EQUALS}}) {
+		movies {
+			id
+			name
+		}
+	}
+}
+`
+
+func FindMoviesByName(
+	ctx_ context.Context,
+	client_ graphql.Client,
+	name string,
+) (data_ *FindMoviesByNameResponse, err_ error) {
+	req_ := &graphql.Request{
+		OpName: "FindMoviesByName",
+		Query:  FindMoviesByName_Operation,
+		Variables: &__FindMoviesByNameInput{
+			Name: name,
+		},
+	}
+
+	data_ = &FindMoviesByNameResponse{}
+	resp_ := &graphql.Response{Data: data_}
+
+	err_ = client_.MakeRequest(
+		ctx_,
+		req_,
+		resp_,
+	)
+
+	return data_, err_
+}
+
 // The query executed by FindPerformerByName.
 const FindPerformerByName_Operation = `
 query FindPerformerByName ($name: String!) {
@@ -4394,6 +5032,43 @@ func FindPerformerByName(
 	return data_, err_
 }
 
+// The query executed by FindPerformersByName.
+const FindPerformersByName_Operation = `
+query FindPerformersByName ($name: String!) {
+	findPerformers(performer_filter: {name:{value:$name,modifier:EQUALS},OR:{aliases:{value:$name,modifier:EQUALS}}}) {
+		performers {
+			id
+			name
+		}
+	}
+}
+`
+
+func FindPerformersByName(
+	ctx_ context.Context,
+	client_ graphql.Client,
+	name string,
+) (data_ *FindPerformersByNameResponse, err_ error) {
+	req_ := &graphql.Request{
+		OpName: "FindPerformersByName",
+		Query:  FindPerformersByName_Operation,
+		Variables: &__FindPerformersByNameInput{
+			Name: name,
+		},
+	}
+
+	data_ = &FindPerformersByNameResponse{}
+	resp_ := &graphql.Response{Data: data_}
+
+	err_ = client_.MakeRequest(
+		ctx_,
+		req_,
+		resp_,
+	)
+
+	return data_, err_
+}
+
 // The query executed by FindSampleSceneCover.
 const FindSampleSceneCover_Operation = `
 query FindSampleSceneCover {
@@ -4602,20 +5277,30 @@ func FindSceneTags(
 	return data_, err_
 }
 
-// The query executed by FindScenes.
-const FindScenes_Operation = `
-query FindScenes ($scene_ids: [Int!]) {
+// The query executed by FindScenesWithGroups.
+const FindScenesWithGroups_Operation = `
+query FindScenesWithGroups ($scene_ids: [Int!]) {
 	findScenes(scene_ids: $scene_ids) {
 		scenes {
 			... SceneParts
+			groups {
+				group {
+					id
+					name
+				}
+				scene_index
+			}
 		}
 	}
 }
 fragment SceneParts on Scene {
 	id
 	title
+	code
+	details
 	rating100
 	created_at
+	updated_at
 	date
 	files {
 		basename
@@ -4625,20 +5310,138 @@ fragment SceneParts on Scene {
 		video_codec
 	}
 	studio {
+		id
 		name
 	}
 	scene_markers {
 		... SceneMarkerParts
 	}
 	performers {
+		id
 		name
 	}
-	groups {
-		group {
-			name
+	play_count
+	resume_time
+	o_counter
+	organized
+	paths {
+		screenshot
+		preview
+		stream
+		funscript
+		interactive_heatmap
+		caption
+	}
+	sceneStreams {
+		url
+		mime_type
+		label
+	}
+	captions {
+		caption_type
+		language_code
+	}
+	interactive
+	... TagPartsArray
+}
+fragment SceneMarkerParts on SceneMarker {
+	id
+	seconds
+	end_seconds
+	title
+	primary_tag {
+		id
+		name
+	}
+}
+fragment TagPartsArray on Scene {
+	tags {
+		... TagParts
+	}
+}
+fragment TagParts on Tag {
+	id
+	name
+	sort_name
+	aliases
+	parents {
+		id
+		name
+		sort_name
+	}
+}
+`
+
+func FindScenesWithGroups(
+	ctx_ context.Context,
+	client_ graphql.Client,
+	scene_ids []int,
+) (data_ *FindScenesWithGroupsResponse, err_ error) {
+	req_ := &graphql.Request{
+		OpName: "FindScenesWithGroups",
+		Query:  FindScenesWithGroups_Operation,
+		Variables: &__FindScenesWithGroupsInput{
+			Scene_ids: scene_ids,
+		},
+	}
+
+	data_ = &FindScenesWithGroupsResponse{}
+	resp_ := &graphql.Response{Data: data_}
+
+	err_ = client_.MakeRequest(
+		ctx_,
+		req_,
+		resp_,
+	)
+
+	return data_, err_
+}
+
+// The query executed by FindScenesWithMovies.
+const FindScenesWithMovies_Operation = `
+query FindScenesWithMovies ($scene_ids: [Int!]) {
+	findScenes(scene_ids: $scene_ids) {
+		scenes {
+			... SceneParts
+			movies {
+				movie {
+					id
+					name
+				}
+				scene_index
+			}
 		}
 	}
+}
+fragment SceneParts on Scene {
+	id
+	title
+	code
+	details
+	rating100
+	created_at
+	updated_at
+	date
+	files {
+		basename
+		duration
+		path
+		height
+		video_codec
+	}
+	studio {
+		id
+		name
+	}
+	scene_markers {
+		... SceneMarkerParts
+	}
+	performers {
+		id
+		name
+	}
 	play_count
+	resume_time
 	o_counter
 	organized
 	paths {
@@ -4689,20 +5492,20 @@ fragment TagParts on Tag {
 }
 `
 
-func FindScenes(
+func FindScenesWithMovies(
 	ctx_ context.Context,
 	client_ graphql.Client,
 	scene_ids []int,
-) (data_ *FindScenesResponse, err_ error) {
+) (data_ *FindScenesWithMoviesResponse, err_ error) {
 	req_ := &graphql.Request{
-		OpName: "FindScenes",
-		Query:  FindScenes_Operation,
-		Variables: &__FindScenesInput{
+		OpName: "FindScenesWithMovies",
+		Query:  FindScenesWithMovies_Operation,
+		Variables: &__FindScenesWithMoviesInput{
 			Scene_ids: scene_ids,
 		},
 	}
 
-	data_ = &FindScenesResponse{}
+	data_ = &FindScenesWithMoviesResponse{}
 	resp_ := &graphql.Response{Data: data_}
 
 	err_ = client_.MakeRequest(
@@ -4717,9 +5520,10 @@ func FindScenes(
 // The query executed by FindStudioByName.
 const FindStudioByName_Operation = `
 query FindStudioByName ($name: String!) {
-	findStudios(studio_filter: {name:{value:$name,modifier:EQUALS}}) {
+	findStudios(studio_filter: {name:{value:$name,modifier:EQUALS},OR:{aliases:{value:$name,modifier:EQUALS}}}) {
 		studios {
 			id
+			name
 		}
 	}
 }
@@ -4872,63 +5676,29 @@ func IsSceneOrganized(
 	return data_, err_
 }
 
-// The mutation executed by SceneAddPlayDurationSeconds.
-const SceneAddPlayDurationSeconds_Operation = `
-mutation SceneAddPlayDurationSeconds ($id: ID!, $seconds: Float) {
-	sceneSaveActivity(id: $id, playDuration: $seconds)
-}
-`
-
-func SceneAddPlayDurationSeconds(
-	ctx_ context.Context,
-	client_ graphql.Client,
-	id string,
-	seconds *float64,
-) (data_ *SceneAddPlayDurationSecondsResponse, err_ error) {
-	req_ := &graphql.Request{
-		OpName: "SceneAddPlayDurationSeconds",
-		Query:  SceneAddPlayDurationSeconds_Operation,
-		Variables: &__SceneAddPlayDurationSecondsInput{
-			Id:      id,
-			Seconds: seconds,
-		},
-	}
-
-	data_ = &SceneAddPlayDurationSecondsResponse{}
-	resp_ := &graphql.Response{Data: data_}
-
-	err_ = client_.MakeRequest(
-		ctx_,
-		req_,
-		resp_,
-	)
-
-	return data_, err_
-}
-
-// The mutation executed by SceneDecrementO.
-const SceneDecrementO_Operation = `
-mutation SceneDecrementO ($id: ID!) {
-	sceneDeleteO(id: $id) {
-		count
+// The mutation executed by PerformerCreate.
+const PerformerCreate_Operation = `
+mutation PerformerCreate ($name: String!) {
+	performerCreate(input: {name:$name}) {
+		id
 	}
 }
 `
 
-func SceneDecrementO(
+func PerformerCreate(
 	ctx_ context.Context,
 	client_ graphql.Client,
-	id string,
-) (data_ *SceneDecrementOResponse, err_ error) {
+	name string,
+) (data_ *PerformerCreateResponse, err_ error) {
 	req_ := &graphql.Request{
-		OpName: "SceneDecrementO",
-		Query:  SceneDecrementO_Operation,
-		Variables: &__SceneDecrementOInput{
-			Id: id,
+		OpName: "PerformerCreate",
+		Query:  PerformerCreate_Operation,
+		Variables: &__PerformerCreateInput{
+			Name: name,
 		},
 	}
 
-	data_ = &SceneDecrementOResponse{}
+	data_ = &PerformerCreateResponse{}
 	resp_ := &graphql.Response{Data: data_}
 
 	err_ = client_.MakeRequest(
@@ -4940,29 +5710,29 @@ func SceneDecrementO(
 	return data_, err_
 }
 
-// The mutation executed by SceneDecrementPlayCount.
-const SceneDecrementPlayCount_Operation = `
-mutation SceneDecrementPlayCount ($id: ID!) {
-	sceneDeletePlay(id: $id) {
-		count
-	}
+// The mutation executed by SceneAddPlayDurationSeconds.
+const SceneAddPlayDurationSeconds_Operation = `
+mutation SceneAddPlayDurationSeconds ($id: ID!, $seconds: Float) {
+	sceneSaveActivity(id: $id, playDuration: $seconds)
 }
 `
 
-func SceneDecrementPlayCount(
+func SceneAddPlayDurationSeconds(
 	ctx_ context.Context,
 	client_ graphql.Client,
 	id string,
-) (data_ *SceneDecrementPlayCountResponse, err_ error) {
+	seconds *float64,
+) (data_ *SceneAddPlayDurationSecondsResponse, err_ error) {
 	req_ := &graphql.Request{
-		OpName: "SceneDecrementPlayCount",
-		Query:  SceneDecrementPlayCount_Operation,
-		Variables: &__SceneDecrementPlayCountInput{
-			Id: id,
+		OpName: "SceneAddPlayDurationSeconds",
+		Query:  SceneAddPlayDurationSeconds_Operation,
+		Variables: &__SceneAddPlayDurationSecondsInput{
+			Id:      id,
+			Seconds: seconds,
 		},
 	}
 
-	data_ = &SceneDecrementPlayCountResponse{}
+	data_ = &SceneAddPlayDurationSecondsResponse{}
 	resp_ := &graphql.Response{Data: data_}
 
 	err_ = client_.MakeRequest(
@@ -5006,74 +5776,6 @@ func SceneDestroy(
 	return data_, err_
 }
 
-// The mutation executed by SceneIncrementO.
-const SceneIncrementO_Operation = `
-mutation SceneIncrementO ($id: ID!) {
-	sceneAddO(id: $id) {
-		count
-	}
-}
-`
-
-func SceneIncrementO(
-	ctx_ context.Context,
-	client_ graphql.Client,
-	id string,
-) (data_ *SceneIncrementOResponse, err_ error) {
-	req_ := &graphql.Request{
-		OpName: "SceneIncrementO",
-		Query:  SceneIncrementO_Operation,
-		Variables: &__SceneIncrementOInput{
-			Id: id,
-		},
-	}
-
-	data_ = &SceneIncrementOResponse{}
-	resp_ := &graphql.Response{Data: data_}
-
-	err_ = client_.MakeRequest(
-		ctx_,
-		req_,
-		resp_,
-	)
-
-	return data_, err_
-}
-
-// The mutation executed by SceneIncrementPlayCount.
-const SceneIncrementPlayCount_Operation = `
-mutation SceneIncrementPlayCount ($id: ID!) {
-	sceneAddPlay(id: $id) {
-		count
-	}
-}
-`
-
-func SceneIncrementPlayCount(
-	ctx_ context.Context,
-	client_ graphql.Client,
-	id string,
-) (data_ *SceneIncrementPlayCountResponse, err_ error) {
-	req_ := &graphql.Request{
-		OpName: "SceneIncrementPlayCount",
-		Query:  SceneIncrementPlayCount_Operation,
-		Variables: &__SceneIncrementPlayCountInput{
-			Id: id,
-		},
-	}
-
-	data_ = &SceneIncrementPlayCountResponse{}
-	resp_ := &graphql.Response{Data: data_}
-
-	err_ = client_.MakeRequest(
-		ctx_,
-		req_,
-		resp_,
-	)
-
-	return data_, err_
-}
-
 // The mutation executed by SceneMarkerCreate.
 const SceneMarkerCreate_Operation = `
 mutation SceneMarkerCreate ($scene_id: ID!, $tag_id: ID!, $seconds: Float!, $end_seconds: Float, $title: String!) {
@@ -5116,48 +5818,6 @@ func SceneMarkerCreate(
 	return data_, err_
 }
 
-// The mutation executed by SceneMarkerUpdate.
-const SceneMarkerUpdate_Operation = `
-mutation SceneMarkerUpdate ($id: ID!, $tag_id: ID!, $seconds: Float!, $end_seconds: Float, $title: String!) {
-	sceneMarkerUpdate(input: {id:$id,primary_tag_id:$tag_id,seconds:$seconds,end_seconds:$end_seconds,title:$title}) {
-		id
-	}
-}
-`
-
-func SceneMarkerUpdate(
-	ctx_ context.Context,
-	client_ graphql.Client,
-	id string,
-	tag_id string,
-	seconds float64,
-	end_seconds *float64,
-	title string,
-) (data_ *SceneMarkerUpdateResponse, err_ error) {
-	req_ := &graphql.Request{
-		OpName: "SceneMarkerUpdate",
-		Query:  SceneMarkerUpdate_Operation,
-		Variables: &__SceneMarkerUpdateInput{
-			Id:          id,
-			Tag_id:      tag_id,
-			Seconds:     seconds,
-			End_seconds: end_seconds,
-			Title:       title,
-		},
-	}
-
-	data_ = &SceneMarkerUpdateResponse{}
-	resp_ := &graphql.Response{Data: data_}
-
-	err_ = client_.MakeRequest(
-		ctx_,
-		req_,
-		resp_,
-	)
-
-	return data_, err_
-}
-
 // The mutation executed by SceneMarkersDestroy.
 const SceneMarkersDestroy_Operation = `
 mutation SceneMarkersDestroy ($ids: [ID!]!) {
@@ -5190,68 +5850,29 @@ func SceneMarkersDestroy(
 	return data_, err_
 }
 
-// The mutation executed by SceneUpdateOrganized.
-const SceneUpdateOrganized_Operation = `
-mutation SceneUpdateOrganized ($id: ID!, $isOrganized: Boolean) {
-	sceneUpdate(input: {id:$id,organized:$isOrganized}) {
-		id
-		organized
-	}
+// The mutation executed by SceneSaveResumeTime.
+const SceneSaveResumeTime_Operation = `
+mutation SceneSaveResumeTime ($id: ID!, $resume_time: Float) {
+	sceneSaveActivity(id: $id, resume_time: $resume_time)
 }
 `
 
-func SceneUpdateOrganized(
+func SceneSaveResumeTime(
 	ctx_ context.Context,
 	client_ graphql.Client,
 	id string,
-	isOrganized *bool,
-) (data_ *SceneUpdateOrganizedResponse, err_ error) {
+	resume_time *float64,
+) (data_ *SceneSaveResumeTimeResponse, err_ error) {
 	req_ := &graphql.Request{
-		OpName: "SceneUpdateOrganized",
-		Query:  SceneUpdateOrganized_Operation,
-		Variables: &__SceneUpdateOrganizedInput{
+		OpName: "SceneSaveResumeTime",
+		Query:  SceneSaveResumeTime_Operation,
+		Variables: &__SceneSaveResumeTimeInput{
 			Id:          id,
-			IsOrganized: isOrganized,
-		},
-	}
-
-	data_ = &SceneUpdateOrganizedResponse{}
-	resp_ := &graphql.Response{Data: data_}
-
-	err_ = client_.MakeRequest(
-		ctx_,
-		req_,
-		resp_,
-	)
-
-	return data_, err_
-}
-
-// The mutation executed by SceneUpdateRating100.
-const SceneUpdateRating100_Operation = `
-mutation SceneUpdateRating100 ($id: ID!, $rating: Int) {
-	sceneUpdate(input: {id:$id,rating100:$rating}) {
-		id
-	}
-}
-`
-
-func SceneUpdateRating100(
-	ctx_ context.Context,
-	client_ graphql.Client,
-	id string,
-	rating *int,
-) (data_ *SceneUpdateRating100Response, err_ error) {
-	req_ := &graphql.Request{
-		OpName: "SceneUpdateRating100",
-		Query:  SceneUpdateRating100_Operation,
-		Variables: &__SceneUpdateRating100Input{
-			Id:     id,
-			Rating: rating,
+			Resume_time: resume_time,
 		},
 	}
 
-	data_ = &SceneUpdateRating100Response{}
+	data_ = &SceneSaveResumeTimeResponse{}
 	resp_ := &graphql.Response{Data: data_}
 
 	err_ = client_.MakeRequest(
@@ -5263,31 +5884,30 @@ func SceneUpdateRating100(
 	return data_, err_
 }
 
-// The mutation executed by SceneUpdateTags.
-const SceneUpdateTags_Operation = `
-mutation SceneUpdateTags ($id: ID!, $tag_ids: [ID!]) {
-	sceneUpdate(input: {id:$id,tag_ids:$tag_ids}) {
+// The mutation executed by SceneUpdate.
+const SceneUpdate_Operation = `
+mutation SceneUpdate ($input: SceneUpdateInput!) {
+	sceneUpdate(input: $input) {
 		id
 	}
 }
 `
 
-func SceneUpdateTags(
+// Fields left nil are omitted and left untouched by Stash, fields set to a pointer to nil are cleared.
+func SceneUpdate(
 	ctx_ context.Context,
 	client_ graphql.Client,
-	id string,
-	tag_ids []string,
-) (data_ *SceneUpdateTagsResponse, err_ error) {
+	input *SceneUpdateInput,
+) (data_ *SceneUpdateResponse, err_ error) {
 	req_ := &graphql.Request{
-		OpName: "SceneUpdateTags",
-		Query:  SceneUpdateTags_Operation,
-		Variables: &__SceneUpdateTagsInput{
-			Id:      id,
-			Tag_ids: tag_ids,
+		OpName: "SceneUpdate",
+		Query:  SceneUpdate_Operation,
+		Variables: &__SceneUpdateInput{
+			Input: input,
 		},
 	}
 
-	data_ = &SceneUpdateTagsResponse{}
+	data_ = &SceneUpdateResponse{}
 	resp_ := &graphql.Response{Data: data_}
 
 	err_ = client_.MakeRequest(
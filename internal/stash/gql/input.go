@@ -0,0 +1,15 @@
+package gql
+
+// SceneGroupInput and SceneMovieInput are bound to the groups and movies of SceneUpdateInput, as pointers to lists so
+// that omitting them leaves groups untouched while an empty list clears them. genqlient doesn't generate input types
+// only reachable through a binding.
+
+type SceneGroupInput struct {
+	Group_id    string `json:"group_id"`
+	Scene_index *int   `json:"scene_index"`
+}
+
+type SceneMovieInput struct {
+	Movie_id    string `json:"movie_id"`
+	Scene_index *int   `json:"scene_index"`
+}
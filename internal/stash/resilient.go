@@ -0,0 +1,175 @@
+package stash
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Khan/genqlient/graphql"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	maxAttempts    = 4
+	backoffBase    = 250 * time.Millisecond
+	backoffMax     = 5 * time.Second
+	breakerLimit   = 5
+	breakerTimeout = 30 * time.Second
+)
+
+var ErrCircuitOpen = errors.New("stash unavailable, circuit breaker open")
+
+// ErrPartiallyApplied wraps errors of writes that failed after Stash already applied some of their mutations.
+var ErrPartiallyApplied = errors.New("partially applied")
+
+// lockedMessages are errors from Stash for writes rolled back because its database was busy, e.g. during a scan.
+var lockedMessages = []string{"database is locked", "database table is locked", "SQLITE_BUSY"}
+
+// resilientClient retries requests failing with transient errors, with exponential backoff and jitter. Queries are
+// retried on any transient error, mutations only if they can't have been applied. Once Stash keeps failing, a circuit
+// breaker fails requests immediately until a trial request succeeds, letting the library fall back to cached data.
+type resilientClient struct {
+	client graphql.Client
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	trialing bool
+}
+
+func newResilientClient(client graphql.Client) *resilientClient {
+	return &resilientClient{client: client}
+}
+
+func (c *resilientClient) MakeRequest(ctx context.Context, req *graphql.Request, resp *graphql.Response) error {
+	isMutation := strings.HasPrefix(strings.TrimSpace(req.Query), "mutation")
+
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			delay := backoff(attempt)
+			log.Ctx(ctx).Debug().Err(err).Str("op", req.OpName).Int("attempt", attempt+1).Dur("delay", delay).Msg("Retrying stash request")
+			select {
+			case <-ctx.Done():
+				return errors.Join(err, ctx.Err())
+			case <-time.After(delay):
+			}
+		}
+
+		if !c.allow() {
+			return ErrCircuitOpen
+		}
+		err = c.client.MakeRequest(ctx, req, resp)
+		transient, applied := classify(err)
+		c.record(ctx, err != nil && transient)
+		// Some mutations of a batch may have been applied before one failed.
+		if req.OpName == batchOpName && len(failedFields(err)) > 0 {
+			applied = true
+		}
+		if err == nil || !transient || (isMutation && applied) || ctx.Err() != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// allow reports whether a request may be made, letting a single trial request through once the breaker timed out.
+func (c *resilientClient) allow() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.failures < breakerLimit {
+		return true
+	}
+	if c.trialing || time.Since(c.openedAt) < breakerTimeout {
+		return false
+	}
+	c.trialing = true
+	return true
+}
+
+func (c *resilientClient) record(ctx context.Context, failed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	wasOpen := c.failures >= breakerLimit
+	c.trialing = false
+	if !failed {
+		if wasOpen {
+			log.Ctx(ctx).Info().Msg("Stash reachable again, circuit breaker closed")
+		}
+		c.failures = 0
+		return
+	}
+	c.failures++
+	if c.failures >= breakerLimit {
+		if !wasOpen {
+			log.Ctx(ctx).Warn().Dur("timeout", breakerTimeout).Msg("Stash keeps failing, circuit breaker opened")
+		}
+		c.openedAt = time.Now()
+	}
+}
+
+// IsCircuitOpen reports whether requests to Stash are currently failing fast.
+func IsCircuitOpen(client graphql.Client) bool {
+	c, ok := client.(*resilientClient)
+	if !ok {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.failures >= breakerLimit
+}
+
+// IsRetryable reports whether a write that failed with err is safe to retry, i.e. Stash was unavailable and didn't
+// apply any of it.
+func IsRetryable(err error) bool {
+	if errors.Is(err, ErrPartiallyApplied) {
+		return false
+	}
+	transient, applied := classify(err)
+	return (transient && !applied) || errors.Is(err, ErrCircuitOpen)
+}
+
+// classify reports whether err is transient, i.e. worth retrying, and whether the request may have been applied by
+// Stash anyway.
+func classify(err error) (transient bool, applied bool) {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false, true
+	}
+
+	for _, m := range lockedMessages {
+		if strings.Contains(err.Error(), m) {
+			return true, false
+		}
+	}
+
+	var httpErr *graphql.HTTPError
+	if errors.As(err, &httpErr) {
+		switch httpErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable:
+			return true, false
+		case http.StatusGatewayTimeout:
+			return true, true
+		}
+		return false, true
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true, false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, net.ErrClosed) || strings.Contains(err.Error(), "EOF") || strings.Contains(err.Error(), "connection reset") {
+		return true, true
+	}
+	return false, true
+}
+
+func backoff(attempt int) time.Duration {
+	d := min(backoffBase<<(attempt-1), backoffMax)
+	return d/2 + rand.N(d/2+1)
+}
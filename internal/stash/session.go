@@ -0,0 +1,120 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// sessionTransport authenticates to Stash with username and password like its login form does. The session cookie is
+// kept in a cookie jar shared with the client, and when Stash responds 401 it logs in again and retries the request.
+type sessionTransport struct {
+	loginUrl string
+	username string
+	password string
+	jar      http.CookieJar
+	rt       http.RoundTripper
+
+	mu         sync.Mutex
+	loggedInAt time.Time
+}
+
+func newSessionTransport(graphqlUrl string, username string, password string, rt http.RoundTripper) (*sessionTransport, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &sessionTransport{
+		loginUrl: strings.TrimSuffix(strings.TrimSuffix(graphqlUrl, "/"), "/graphql") + "/login",
+		username: username,
+		password: password,
+		jar:      jar,
+		rt:       rt,
+	}, nil
+}
+
+func (t *sessionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.rt.RoundTrip(t.withSession(req))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	if err := t.login(req, start); err != nil {
+		log.Ctx(req.Context()).Warn().Err(err).Msg("Stash login failed")
+		return resp, nil
+	}
+	_ = resp.Body.Close()
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	retry.Header.Del("Cookie")
+	return t.rt.RoundTrip(t.withSession(retry))
+}
+
+// withSession adds the session cookie to requests made with the transport directly instead of through the client,
+// e.g. proxied media.
+func (t *sessionTransport) withSession(req *http.Request) *http.Request {
+	if req.Header.Get("Cookie") != "" {
+		return req
+	}
+	cookies := t.jar.Cookies(req.URL)
+	if len(cookies) == 0 {
+		return req
+	}
+	req = req.Clone(req.Context())
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	return req
+}
+
+// login logs in unless another request already did after the failed request was made.
+func (t *sessionTransport) login(req *http.Request, failedAt time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.loggedInAt.After(failedAt) {
+		return nil
+	}
+
+	form := url.Values{"username": {t.username}, "password": {t.password}}
+	loginReq, err := http.NewRequestWithContext(req.Context(), http.MethodPost, t.loginUrl, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	loginReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := &http.Client{
+		Transport: t.rt,
+		Jar:       t.jar,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Do(loginReq)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	// Stash redirects on success and serves the login page again on invalid credentials.
+	if resp.StatusCode != http.StatusFound && resp.StatusCode != http.StatusSeeOther {
+		return fmt.Errorf("invalid credentials or unexpected response: %s", resp.Status)
+	}
+
+	t.loggedInAt = time.Now()
+	log.Ctx(req.Context()).Debug().Str("username", t.username).Msg("Logged in to Stash")
+	return nil
+}
@@ -0,0 +1,101 @@
+package trash
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"stash-vr/internal/config"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	trashFile = "trash.json"
+)
+
+// Store holds when scenes were moved to the trash, so that they are purged once TRASH_RETENTION has passed. Times are
+// saved to CONFIG_PATH, or kept in memory only if CONFIG_PATH is not specified.
+type Store struct {
+	mu      sync.RWMutex
+	trashed map[string]time.Time
+}
+
+func Open(ctx context.Context) *Store {
+	s := &Store{trashed: make(map[string]time.Time)}
+	if config.Application().ConfigPath == "" {
+		return s
+	}
+
+	path := resolvePath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to read trash file")
+		}
+		return s
+	}
+	if err := json.Unmarshal(data, &s.trashed); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to parse trash file")
+		s.trashed = make(map[string]time.Time)
+	}
+	return s
+}
+
+// Add records that a scene was moved to the trash now, unless it already was.
+func (s *Store) Add(ctx context.Context, sceneId string) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t, ok := s.trashed[sceneId]; ok {
+		return t
+	}
+	now := time.Now()
+	s.trashed[sceneId] = now
+	s.save(ctx)
+	return now
+}
+
+// Remove forgets a scene that was restored or purged.
+func (s *Store) Remove(ctx context.Context, sceneId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.trashed[sceneId]; !ok {
+		return
+	}
+	delete(s.trashed, sceneId)
+	s.save(ctx)
+}
+
+// Trashed returns when each scene in the trash was moved there.
+func (s *Store) Trashed() map[string]time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return maps.Clone(s.trashed)
+}
+
+func (s *Store) save(ctx context.Context) {
+	if config.Application().ConfigPath == "" {
+		return
+	}
+	path := resolvePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("error creating config directory")
+		return
+	}
+	data, err := json.MarshalIndent(s.trashed, "", "  ")
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to encode trash")
+		return
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("failed to save trash")
+	}
+}
+
+func resolvePath() string {
+	return filepath.Join(config.Application().ConfigPath, trashFile)
+}
@@ -6,3 +6,13 @@ func Abs(x int) int {
 	}
 	return x
 }
+
+func Sign(x int) int {
+	switch {
+	case x < 0:
+		return -1
+	case x > 0:
+		return 1
+	}
+	return 0
+}
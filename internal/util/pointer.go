@@ -3,3 +3,20 @@ package util
 func Ptr[T any](v T) *T {
 	return &v
 }
+
+// PtrEqual reports whether both pointers are nil or point to equal values.
+func PtrEqual[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// Deref returns the value p points to, or the zero value if p is nil.
+func Deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
@@ -20,3 +20,11 @@ func StrSliceEquals(s string, ss []string, v string) bool {
 		return strings.ToLower(el) == v
 	})
 }
+
+// NilIfEmpty returns a pointer to s, or nil if s is empty.
+func NilIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}